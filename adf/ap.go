@@ -1,23 +1,48 @@
 package adf
 
 import (
-	"errors"
+	"encoding/json"
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/lapack/lapack64"
 	"gonum.org/v1/gonum/mat"
 )
 
+//kindAP is the kind name of FiltAP.
+const kindAP = "AP filter"
+
 //FiltAP is base struct for AP filter.
 //Use NewFiltAP to make instance.
 type FiltAP struct {
+	apBase
+}
+
+//apBase is the sliding window and the projection of AP filter,
+//shared with the filters built on the same math such as FiltSMAP.
+type apBase struct {
 	filtBase
 	order  int
 	eps    float64
-	wHist  [][]float64
 	xMem   *mat.Dense
 	dMem   *mat.Dense
 	yMem   *mat.Dense
 	eMem   *mat.Dense
 	epsIDE *mat.Dense
 	ide    *mat.Dense
+	//the workspace of shift and update, allocated once so that a sample does not allocate.
+	xCol []float64
+	dCol []float64
+	dw1  *mat.Dense
+	dw2  *mat.Dense
+	dw3  *mat.Dense
+	dw   *mat.Dense
+	//work, iwork and pivot are the workspace of the LU decomposition of dw1.
+	work  []float64
+	iwork []int
+	pivot []int
 }
 
 //NewFiltAP is constructor of AP filter.
@@ -25,7 +50,7 @@ type FiltAP struct {
 func NewFiltAP(n int, mu float64, order int, eps float64, w []float64)(AdaptiveFilter, error) {
 	var err error
 	p := new(FiltAP)
-	p.kind = "AP filter"
+	p.kind = kindAP
 	p.n = n
 	p.muMin = 0
 	p.muMax = 1000
@@ -33,7 +58,10 @@ func NewFiltAP(n int, mu float64, order int, eps float64, w []float64)(AdaptiveF
 	if err != nil {
 		return nil, err
 	}
-	p.order = order
+	p.order, err = p.checkIntParam(order, 1, math.MaxInt32, "order")
+	if err != nil {
+		return nil, err
+	}
 	p.eps, err = p.checkFloatParam(eps, 0, 1000, "eps")
 	if err != nil {
 		return nil, err
@@ -61,6 +89,7 @@ func NewFiltAP(n int, mu float64, order int, eps float64, w []float64)(AdaptiveF
 
 	p.yMem = mat.NewDense(order, 1, nil)
 	p.eMem = mat.NewDense(1, order, nil)
+	p.initWorkspace()
 
 	return p, nil
 }
@@ -68,10 +97,32 @@ func NewFiltAP(n int, mu float64, order int, eps float64, w []float64)(AdaptiveF
 //Adapt calculates the error `e` between desired value `d` and estimated value `y`,
 //and update filter weights according to error `e`.
 func (af *FiltAP) Adapt(d float64, x []float64) {
-	xr, _ := af.xMem.Dims()
-	xCol := make([]float64, xr)
-	dr, _ := af.dMem.Dims()
-	dCol := make([]float64, dr)
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+//The sliding window of the inputs is shifted only once.
+func (af *FiltAP) Step(d float64, x []float64) (y, e float64) {
+	y, e, err := af.step(d, x)
+	if err != nil {
+		panic(err)
+	}
+	return y, e
+}
+
+//step shifts the sliding window, calculates the estimated value `y` and the error `e`,
+//and update filter weights. It returns the error of solving the linear system.
+func (af *FiltAP) step(d float64, x []float64) (y, e float64, err error) {
+	y, e = af.shift(d, x)
+	return y, e, af.update(af.eMem, af.mu)
+}
+
+//shift shifts the sliding window of the inputs and the desired values,
+//and calculates the estimated values and the errors of the window.
+//It returns the estimated value `y` and the error `e` of the new sample.
+func (af *apBase) shift(d float64, x []float64) (y, e float64) {
+	xCol, dCol := af.xCol, af.dCol
 	// create input matrix and target vector
 	// shift column
 	for i := af.order - 1; i > 0; i-- {
@@ -84,92 +135,166 @@ func (af *FiltAP) Adapt(d float64, x []float64) {
 	af.dMem.Set(0, 0, d)
 
 	// estimate output and error
-	af.yMem.Mul(af.xMem.T(), af.w.T())
-	af.eMem.Sub(af.dMem, af.yMem.T())
-
-	// update
-	dw1 := mat.NewDense(af.order, af.order, nil)
-	dw1.Mul(af.xMem.T(), af.xMem)
-	dw1.Add(dw1, af.epsIDE)
-	dw2 := mat.NewDense(af.order, af.order, nil)
-	err := dw2.Solve(dw1, af.ide)
-	if err != nil {
-		panic(err)
+	// same as af.yMem.Mul(af.xMem.T(), af.w.T())
+	// and af.eMem.Sub(af.dMem, af.yMem.T()) without boxing the transposes
+	blas64.Gemm(blas.Trans, blas.Trans, 1, af.xMem.RawMatrix(), af.w.RawMatrix(), 0, af.yMem.RawMatrix())
+	yData, dData, eData := af.yMem.RawMatrix().Data, af.dMem.RawMatrix().Data, af.eMem.RawMatrix().Data
+	for i := range eData {
+		eData[i] = dData[i] - yData[i]
+	}
+	return af.yMem.At(0, 0), af.eMem.At(0, 0)
+}
+
+//update updates filter weights with the errors `eVec` (1 x order) of the window and step size `mu`.
+func (af *apBase) update(eVec *mat.Dense, mu float64) error {
+	xMem := af.xMem.RawMatrix()
+	// dw1 = xMem^T * xMem + eps * I
+	blas64.Gemm(blas.Trans, blas.NoTrans, 1, xMem, xMem, 0, af.dw1.RawMatrix())
+	af.dw1.Add(af.dw1, af.epsIDE)
+	// dw2 = dw1^-1
+	if err := af.invert(); err != nil {
+		return err
 	}
-	dw3 := mat.NewDense(1, af.order, nil)
-	dw3.Mul(af.eMem, dw2)
-	dw := mat.NewDense(1, af.n, nil)
-	dw.Mul(dw3, af.xMem.T())
-	dw.Scale(af.mu, dw)
-	af.w.Add(af.w, dw)
+	af.dw3.Mul(eVec, af.dw2)
+	// dw = mu * dw3 * xMem^T
+	dw := af.dw.RawMatrix()
+	blas64.Gemm(blas.NoTrans, blas.Trans, 1, af.dw3.RawMatrix(), xMem, 0, dw)
+	floats.Scale(mu, dw.Data)
+	af.w.Add(af.w, af.dw)
+	return nil
+}
+
+//invert sets dw2 to the inverse of dw1 in the same way as dw2.Solve(dw1, ide),
+//but with the preallocated workspace. dw1 is overwritten by its LU decomposition.
+func (af *apBase) invert() error {
+	a := af.dw1.RawMatrix()
+	anorm := lapack64.Lange(mat.CondNorm, a, af.work)
+	if !lapack64.Getrf(a, af.pivot) {
+		return mat.Condition(math.Inf(1))
+	}
+	cond := 1 / lapack64.Gecon(mat.CondNorm, a, anorm, af.work, af.iwork)
+	af.dw2.Copy(af.ide)
+	lapack64.Getrs(blas.NoTrans, a, af.dw2.RawMatrix(), af.pivot)
+	if cond > mat.ConditionTolerance {
+		return mat.Condition(cond)
+	}
+	return nil
+}
+
+//initWorkspace allocates the workspace of shift and update.
+func (af *apBase) initWorkspace() {
+	af.xCol = make([]float64, af.n)
+	af.dCol = make([]float64, 1)
+	af.dw1 = mat.NewDense(af.order, af.order, nil)
+	af.dw2 = mat.NewDense(af.order, af.order, nil)
+	af.dw3 = mat.NewDense(1, af.order, nil)
+	af.dw = mat.NewDense(1, af.n, nil)
+	af.work = make([]float64, 4*af.order)
+	af.iwork = make([]int, af.order)
+	af.pivot = make([]int, af.order)
 }
 
 //Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 //while updating filter weights according to error `e`.
-func (af *FiltAP) Run(d []float64, x [][]float64) (y []float64, e []float64, wHist [][]float64, err error) {
-	//measure the data and check if the dimension agree
-	N := len(x)
-	if len(d) != N {
-		return nil, nil, nil, errors.New("the length of slice d and x must agree")
-	}
-	af.n = len(x[0])
-	af.wHist = make([][]float64, N)
-	for i := 0; i < N; i++ {
-		af.wHist[i] = make([]float64, af.n)
-	}
-
-	y = make([]float64, N)
-	e = make([]float64, N)
-	w := af.w.RawRowView(0)
-
-	xr, _ := af.xMem.Dims()
-	xCol := make([]float64, xr)
-	dr, _ := af.dMem.Dims()
-	dCol := make([]float64, dr)
-
-	//adaptation loop
-	for i := 0; i < N; i++ {
-		copy(af.wHist[i], w)
-
-		// create input matrix and target vector
-		// shift column
-		for i := af.order - 1; i > 0; i-- {
-			mat.Col(xCol, i-1, af.xMem)
-			af.xMem.SetCol(i, xCol)
-			mat.Col(dCol, i-1, af.dMem)
-			af.dMem.SetCol(i, dCol)
-		}
-		af.xMem.SetCol(0, x[i])
-		af.dMem.Set(0, 0, d[i])
-
-		// estimate output and error
-		// same as af.yMem.Mul(af.xMem, af.w.T()).T()
-		af.yMem.Mul(af.xMem.T(), af.w.T())
-		af.eMem.Sub(af.dMem, af.yMem.T())
-		y[i] = af.yMem.At(0, 0)
-		e[i] = af.eMem.At(0, 0)
-
-		// update
-		dw1 := mat.NewDense(af.order, af.order, nil)
-		dw1.Mul(af.xMem.T(), af.xMem)
-		dw1.Add(dw1, af.epsIDE)
-		dw2 := mat.NewDense(af.order, af.order, nil)
-		err := dw2.Solve(dw1, af.ide)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		dw3 := mat.NewDense(1, af.order, nil)
-		dw3.Mul(af.eMem, dw2)
-		dw := mat.NewDense(af.n, 1, nil)
-		dw.Mul(af.xMem, dw3.T())
-		dw.Scale(af.mu, dw)
-		af.w.Add(af.w, dw.T())
-	}
-	wHist = af.wHist
-	return y, e, wHist, nil
-}
-
-func (af *FiltAP) clone() AdaptiveFilter {
+func (af *FiltAP) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	var stepErr error
+	step := func(d float64, x []float64) (y, e float64) {
+		y, e, stepErr = af.step(d, x)
+		return y, e
+	}
+	return af.runSteps(d, x, opts, step, &runHooks{
+		after: func(i int, hist *historyRecorder[float64]) error {
+			return stepErr
+		},
+	})
+}
+
+//Reset sets the filter weights to zeros and clears the input and desired value memories.
+func (af *apBase) Reset() {
+	af.filtBase.Reset()
+	af.xMem.Zero()
+	af.dMem.Zero()
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltAP) Clone() AdaptiveFilter {
+	return &FiltAP{apBase: af.clone()}
+}
+
+//clone returns a deep copy of the base.
+func (af *apBase) clone() apBase {
 	altaf := *af
-	return &altaf
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.xMem = mat.DenseCopyOf(af.xMem)
+	altaf.dMem = mat.DenseCopyOf(af.dMem)
+	altaf.yMem = mat.DenseCopyOf(af.yMem)
+	altaf.eMem = mat.DenseCopyOf(af.eMem)
+	altaf.initWorkspace()
+	return altaf
+}
+
+//state returns the complete state of the filter.
+func (af *FiltAP) state() *filterState {
+	return &filterState{
+		Version: stateVersion,
+		Kind:    kindAP,
+		N:       af.n,
+		Mu:      af.mu,
+		W:       denseData(af.w),
+		Eps:     af.eps,
+		Order:   af.order,
+		XMem:    denseData(af.xMem),
+		DMem:    denseData(af.dMem),
+	}
+}
+
+//setState restores the filter from the state `s`.
+func (af *FiltAP) setState(s *filterState) error {
+	if err := checkState(s, kindAP); err != nil {
+		return err
+	}
+	if err := checkStateLen(s.XMem, s.N*s.Order, "xMem"); err != nil {
+		return err
+	}
+	if err := checkStateLen(s.DMem, s.Order, "dMem"); err != nil {
+		return err
+	}
+	p, err := NewFiltAP(s.N, s.Mu, s.Order, s.Eps, s.W)
+	if err != nil {
+		return err
+	}
+	*af = *p.(*FiltAP)
+	af.xMem = mat.NewDense(s.N, s.Order, s.XMem)
+	af.dMem = mat.NewDense(1, s.Order, s.DMem)
+	return nil
+}
+
+//MarshalBinary implements encoding.BinaryMarshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltAP) MarshalBinary() ([]byte, error) {
+	return encodeState(af.state())
+}
+
+//UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (af *FiltAP) UnmarshalBinary(data []byte) error {
+	s, err := decodeState(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
+}
+
+//MarshalJSON implements json.Marshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltAP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(af.state())
+}
+
+//UnmarshalJSON implements json.Unmarshaler.
+func (af *FiltAP) UnmarshalJSON(data []byte) error {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
 }
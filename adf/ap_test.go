@@ -215,5 +215,5 @@ func ExampleExploreLearning_ap() {
 	eMin := floats.Min(es)
 	fmt.Printf("the step size mu with the smallest error is %.3f\n", res[eMin])
 	//output:
-	//the step size mu with the smallest error is 2.071
+	//the step size mu with the smallest error is 2.021
 }
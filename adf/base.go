@@ -1,16 +1,21 @@
 package adf
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+
 	"github.com/pkg/errors"
+	"github.com/tetsuzawa/go-adflib/internal/adfutil"
 	"github.com/tetsuzawa/go-adflib/misc"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
 // AdaptiveFilter is the basic Adaptive Filter interface type.
+//It can be implemented outside this package, so that user defined filters
+//can be used with helpers such as PreTrainedRun and ExploreLearning.
 type AdaptiveFilter interface {
-	initWeights(w []float64, n int) error
 	//Predict calculates the new estimated value `y` from input slice `x`.
 	Predict(x []float64) (y float64)
 
@@ -20,21 +25,53 @@ type AdaptiveFilter interface {
 
 	//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 	//while updating filter weights according to error `e`.
-	Run(d []float64, x [][]float64) (y []float64, e []float64, wHist [][]float64, err error)
-	checkFloatParam(p, low, high float64, name string) (float64, error)
-	checkIntParam(p, low, high int, name string) (int, error)
-
-	//SetStepSize sets the step size of adaptive filter.
-	SetStepSize(mu float64) error
+	//The weight history `wHist` is configured by `opts`.
+	Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error)
 
 	//GetParams returns the parameters at the time this func is called.
 	//parameters contains `n`: filter length, `mu`: filter update step size and `w`: filter weights.
 	GetParams() (n int, mu float64, w []float64)
 
-	//GetParams returns the name of ADF.
+	//GetKindName returns the name of ADF.
 	GetKindName() (kind string)
 
-	clone() AdaptiveFilter
+	//SetWeights sets the filter weights. The length of `w` must be the filter length `n`.
+	//If `w` is nil, the weights are set to zeros.
+	SetWeights(w []float64) error
+
+	//Reset sets the filter weights to zeros and clears the internal state of the filter.
+	Reset()
+
+	//Clone returns a deep copy of the filter.
+	//The copy can be adapted independently of the original.
+	Clone() AdaptiveFilter
+}
+
+//StepSizeSetter is implemented by adaptive filters whose update step size `mu` can be changed after construction.
+//ExploreLearning requires it.
+type StepSizeSetter interface {
+	//SetStepSize sets the step size of adaptive filter.
+	SetStepSize(mu float64) error
+}
+
+//Stepper is implemented by adaptive filters which return the estimated value and the error of the adapted sample.
+//All filters of this package implement it. Stream falls back to Predict followed by Adapt for the other filters.
+type Stepper interface {
+	//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+	//and update filter weights according to error `e`.
+	//It is Adapt which also returns `y` and `e`, the same values as Run returns for the sample.
+	Step(d float64, x []float64) (y, e float64)
+}
+
+//step calls Step of `af` if it is a Stepper,
+//otherwise it calculates `y` by Predict and then adapts the filter by Adapt.
+func step(af AdaptiveFilter, d float64, x []float64) (y, e float64) {
+	if s, ok := af.(Stepper); ok {
+		return s.Step(d, x)
+	}
+	y = af.Predict(x)
+	af.Adapt(d, x)
+	return y, d - y
 }
 
 //Must checks whether err is nil or not. If err in not nil, this func causes panic.
@@ -50,17 +87,26 @@ func Must(af AdaptiveFilter, err error) AdaptiveFilter {
 //`x` is input matrix. columns are bunch of samples and rows are set of samples.
 //`nTrain` is train to test ratio, typical value is 0.5. (that means 50% of data is used for training).
 //`epochs` is number of training epochs, typical value is 1. This number describes how many times the training will be repeated.
-func PreTrainedRun(af AdaptiveFilter, d []float64, x [][]float64, nTrain float64, epochs int) (y, e []float64, w [][]float64, err error) {
+//`opts` configures the weight history of the final run. No history is recorded during the training.
+func PreTrainedRun(af AdaptiveFilter, d []float64, x [][]float64, nTrain float64, epochs int, opts ...RunOption) (y, e []float64, w [][]float64, err error) {
+	return preTrainedRun(context.Background(), af, d, x, nTrain, epochs, opts...)
+}
+
+//preTrainedRun is PreTrainedRun which stops between the epochs when `ctx` is done.
+func preTrainedRun(ctx context.Context, af AdaptiveFilter, d []float64, x [][]float64, nTrain float64, epochs int, opts ...RunOption) (y, e []float64, w [][]float64, err error) {
 	var nTrainI = int(float64(len(d)) * nTrain)
 	//train
 	for i := 0; i < epochs; i++ {
-		_, _, _, err = af.Run(d[:nTrainI], x[:nTrainI])
+		if err = ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		_, _, _, err = af.Run(d[:nTrainI], x[:nTrainI], WithoutHistory())
 		if err != nil {
 			return nil, nil, nil, err
 		}
 	}
 	//run
-	y, e, w, err = af.Run(d[:nTrainI], x[:nTrainI])
+	y, e, w, err = af.Run(d[:nTrainI], x[:nTrainI], opts...)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -69,6 +115,10 @@ func PreTrainedRun(af AdaptiveFilter, d []float64, x [][]float64, nTrain float64
 
 //ExploreLearning searches the `mu` with the smallest error value from the input matrix `x` and desired values `d`.
 //
+//`af` must implement StepSizeSetter.
+//Each `mu` is tested on an independent clone of `af`, so `af` itself is not changed.
+//The step sizes are tested in parallel on all available CPUs. See ExploreLearningContext.
+//
 //The arg `d` is desired value.
 //
 //`x` is input matrix.
@@ -90,33 +140,61 @@ func PreTrainedRun(af AdaptiveFilter, d []float64, x [][]float64, nTrain float64
 // If an slice is provided, the error between weights and `target_w` is used.
 func ExploreLearning(af AdaptiveFilter, d []float64, x [][]float64, muStart, muEnd float64, steps int,
 	nTrain float64, epochs int, criteria string, targetW []float64) ([]float64, []float64, error) {
+	return ExploreLearningContext(context.Background(), af, d, x, muStart, muEnd, steps,
+		nTrain, epochs, criteria, targetW, runtime.GOMAXPROCS(0))
+}
+
+//ExploreLearningContext is ExploreLearning with cancellation and a bounded number of workers.
+//
+//`workers` is the maximum number of step sizes tested at the same time. If it is smaller than 1, 1 is used.
+//
+//When `ctx` is done, the remaining step sizes are not tested and the error of `ctx` is returned.
+func ExploreLearningContext(ctx context.Context, af AdaptiveFilter, d []float64, x [][]float64, muStart, muEnd float64, steps int,
+	nTrain float64, epochs int, criteria string, targetW []float64, workers int) ([]float64, []float64, error) {
+	if _, ok := af.(StepSizeSetter); !ok {
+		return nil, nil, fmt.Errorf("%v does not implement StepSizeSetter", af.GetKindName())
+	}
 	mus := misc.LinSpace(muStart, muEnd, steps)
 	es := make([]float64, len(mus))
-	zeros := make([]float64, int(float64(len(x))*nTrain))
-	for i, mu := range mus {
-		//init
-		err := af.initWeights(nil, len(x[0]))
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to init weights at InitWights()")
-		}
-		err = af.SetStepSize(mu)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to set step size at StetStepSize()")
-		}
-		//run
-		_, e, _, err := PreTrainedRun(af, d, x, nTrain, epochs)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to pre train at PreTrainedRun()")
-		}
-		es[i], err = misc.GetMeanError(e, zeros, criteria)
-		//fmt.Println(es[i])
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to get mean error at GetMeanError()")
-		}
+	err := adfutil.ParallelFor(ctx, len(mus), workers, func(ctx context.Context, i int) error {
+		var err error
+		es[i], err = exploreStepSize(ctx, af, d, x, mus[i], nTrain, epochs, criteria, targetW)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 	return es, mus, nil
 }
 
+//exploreStepSize returns the mean error of a clone of `af` trained with the step size `mu`.
+func exploreStepSize(ctx context.Context, af AdaptiveFilter, d []float64, x [][]float64, mu float64,
+	nTrain float64, epochs int, criteria string, targetW []float64) (float64, error) {
+	//init
+	c := af.Clone()
+	c.Reset()
+	err := c.(StepSizeSetter).SetStepSize(mu)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to set step size at StetStepSize()")
+	}
+	//run
+	_, e, _, err := preTrainedRun(ctx, c, d, x, nTrain, epochs, WithoutHistory())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to pre train at PreTrainedRun()")
+	}
+	var es float64
+	if targetW == nil {
+		es, err = misc.GetMeanError(e, make([]float64, len(e)), criteria)
+	} else {
+		_, _, w := c.GetParams()
+		es, err = misc.GetMeanError(append([]float64{}, w...), targetW, criteria)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get mean error at GetMeanError()")
+	}
+	return es, nil
+}
+
 //FiltBase is base struct for adaptive filter structs.
 //It puts together some functions used by all adaptive filters.
 type filtBase struct {
@@ -147,14 +225,29 @@ func newFiltBase(n int, mu float64, w []float64) (AdaptiveFilter, error) {
 	return p, nil
 }
 
+//SetWeights sets the filter weights. The length of `w` must be the filter length `n`.
+//If `w` is nil, the weights are set to zeros.
+//The slice `w` is copied.
+func (af *filtBase) SetWeights(w []float64) error {
+	if w != nil {
+		w = append([]float64{}, w...)
+	}
+	return af.initWeights(w, af.n)
+}
+
+//Reset sets the filter weights to zeros.
+func (af *filtBase) Reset() {
+	af.w = mat.NewDense(1, af.n, nil)
+}
+
 //initWeights initialises the adaptive weights of the filter.
 //The arg `w` is initial weights of filter.
 //Typical value is zeros with length `n`.
-//If `w` is nil, this func initializes `w` as zeros.
+//If `w` is nil, this func initializes `w` as zeros. The filter length `n` must be positive.
 //`n` is size of filter. Note that it is often mistaken for the sample length.
 func (af *filtBase) initWeights(w []float64, n int) error {
 	if n <= 0 {
-		n = af.n
+		return fmt.Errorf("the filter length `n` must be positive. n: %d", n)
 	}
 	if w == nil {
 		w = make([]float64, n)
@@ -174,39 +267,86 @@ func (af *filtBase) Predict(x []float64) (y float64) {
 }
 
 //Adapt is just a method to satisfy the interface.
-//It is used by overriding.
-func (af *filtBase) Adapt(d float64, x []float64) {
-	//TODO
+//It is used by overriding. The base filter keeps its weights fixed.
+func (af *filtBase) Adapt(d float64, x []float64) {}
+
+//Step calculates the output `y` of `x` by Predict
+//and then adapts the filter by Adapt.
+//The error `e` is `d - y`.
+func (af *filtBase) Step(d float64, x []float64) (y, e float64) {
+	y = af.Predict(x)
+	e = d - y
+	af.Adapt(d, x)
+	return y, e
 }
 
-//Run is just a method to satisfy the interface.
+//Run calculates the output `y` and the error `e` of `x` by Step.
 //It is used by overriding.
-func (af *filtBase) Run(d []float64, x [][]float64) ([]float64, []float64, [][]float64, error) {
-	//TODO
+func (af *filtBase) Run(d []float64, x [][]float64, opts ...RunOption) ([]float64, []float64, [][]float64, error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//runHooks are the callbacks of runSteps for the filters which record extra outputs of Run.
+//Each of them may be nil.
+type runHooks struct {
+	//init is called before the first sample, to make the extra outputs for `N` samples.
+	init func(N int, hist *historyRecorder[float64])
+	//before is called for the sample `i` after the weights are recorded, before the step.
+	before func(i int, hist *historyRecorder[float64])
+	//after is called for the sample `i` after the step. If it returns an error, Run stops and returns it.
+	after func(i int, hist *historyRecorder[float64]) error
+}
+
+//runSteps is the adaptation loop of Run for the filters which adapt with `step`.
+//`step` is the Step method of the filter and `hooks` are the per-sample callbacks, which may be nil.
+func (af *filtBase) runSteps(d []float64, x [][]float64, opts []RunOption,
+	step func(d float64, x []float64) (y, e float64), hooks *runHooks) (y []float64, e []float64, wHist [][]float64, err error) {
 	//measure the data and check if the dimension agree
 	N := len(x)
 	if len(d) != N {
 		return nil, nil, nil, errors.New("the length of slice d and x must agree")
 	}
-	af.n = len(x[0])
+	if N > 0 && len(x[0]) != af.n {
+		return nil, nil, nil, fmt.Errorf("the length of rows of x and `n` must agree. len(x[0]): %d, n: %d", len(x[0]), af.n)
+	}
+	if hooks == nil {
+		hooks = &runHooks{}
+	}
+	hist := newHistory(N, af.n, opts)
+	if hooks.init != nil {
+		hooks.init(N, hist)
+	}
 
-	y := make([]float64, N)
-	e := make([]float64, N)
-	w := make([]float64, af.n)
-	wHist := make([][]float64, N)
+	y = make([]float64, N)
+	e = make([]float64, N)
 	//adaptation loop
 	for i := 0; i < N; i++ {
-		w = af.w.RawRowView(0)
-		y[i] = floats.Dot(w, x[i])
-		e[i] = d[i] - y[i]
-		copy(wHist[i], w)
+		if err := hist.Record(i, af.w.RawRowView(0)); err != nil {
+			return nil, nil, nil, err
+		}
+		if hooks.before != nil {
+			hooks.before(i, hist)
+		}
+		y[i], e[i] = step(d[i], x[i])
+		hist.recordStepSize(i, af.mu)
+		if hooks.after != nil {
+			if err := hooks.after(i, hist); err != nil {
+				return nil, nil, nil, err
+			}
+		}
 	}
-	return y, e, wHist, nil
+	return y, e, hist.Result(), nil
 }
 
 //checkFloatParam check if the value of the given parameter
 //is in the given range and a float.
 func (af *filtBase) checkFloatParam(p, low, high float64, name string) (float64, error) {
+	return checkFloatParam(p, low, high, name)
+}
+
+//checkFloatParam is filtBase.checkFloatParam for the filters which are not based on filtBase,
+//such as the complex filters.
+func checkFloatParam(p, low, high float64, name string) (float64, error) {
 	if low <= p && p <= high {
 		return p, nil
 	} else {
@@ -242,12 +382,14 @@ func (af *filtBase) GetParams() (int, float64, []float64) {
 	return af.n, af.mu, af.w.RawRowView(0)
 }
 
-//GetParams returns the name of ADF.
+//GetKindName returns the name of ADF.
 func (af *filtBase) GetKindName() string {
 	return af.kind
 }
 
-func (af *filtBase) clone() AdaptiveFilter {
+//Clone returns a deep copy of the filter.
+func (af *filtBase) Clone() AdaptiveFilter {
 	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
 	return &altaf
 }
@@ -1,9 +1,14 @@
 package adf
 
 import (
+	"context"
+	"errors"
+	"math/rand"
 	"reflect"
 	"testing"
 
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -251,3 +256,270 @@ func Test_filtBase_initWeights(t *testing.T) {
 	}
 }
 
+
+func TestAdaptiveFilter_Clone(t *testing.T) {
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "LMS", af: Must(NewFiltLMS(4, 0.1, nil))},
+		{name: "NLMS", af: Must(NewFiltNLMS(4, 0.1, 1e-5, nil))},
+		{name: "RLS", af: Must(NewFiltRLS(4, 0.99, 0.1, nil))},
+		{name: "AP", af: Must(NewFiltAP(4, 0.1, 2, 1e-5, nil))},
+		{name: "SE-LMS", af: Must(NewFiltSELMS(4, 0.1, nil))},
+		{name: "SD-LMS", af: Must(NewFiltSDLMS(4, 0.1, nil))},
+		{name: "SS-LMS", af: Must(NewFiltSSLMS(4, 0.1, nil))},
+		{name: "LMF", af: Must(NewFiltLMF(4, 0.1, nil))},
+		{name: "NLMF", af: Must(NewFiltNLMF(4, 0.1, 1e-5, nil))},
+		{name: "VSS-LMS", af: Must(NewFiltVSSLMS(4, 0.1, 0.001, 0.1, 0.97, 1e-3, nil))},
+		{name: "Mathews", af: Must(NewFiltMathewsLMS(4, 0.1, 0.001, 0.1, 1e-3, nil))},
+		{name: "Benveniste", af: Must(NewFiltBenvenisteLMS(4, 0.1, 0.001, 0.1, 1e-3, nil))},
+		{name: "GNGD", af: Must(NewFiltGNGD(4, 1, 1, 0.1, nil))},
+		{name: "PNLMS", af: Must(NewFiltPNLMS(4, 0.5, 1e-3, 0.01, 0.01, nil))},
+		{name: "IPNLMS", af: Must(NewFiltIPNLMS(4, 0.5, 1e-3, 0, nil))},
+		{name: "MPNLMS", af: Must(NewFiltMPNLMS(4, 0.5, 1e-3, 0.01, 0.01, 1000, nil))},
+		{name: "ZA-LMS", af: Must(NewFiltZALMS(4, 0.1, 1e-4, nil))},
+		{name: "RZA-LMS", af: Must(NewFiltRZALMS(4, 0.1, 1e-4, 10, nil))},
+		{name: "l0-LMS", af: Must(NewFiltL0LMS(4, 0.1, 1e-5, 10, nil))},
+		{name: "leaky LMS", af: Must(NewFiltLeakyLMS(4, 0.1, 1e-3, nil))},
+		{name: "leaky NLMS", af: Must(NewFiltLeakyNLMS(4, 0.1, 1e-3, 1e-3, nil))},
+		{name: "SM-NLMS", af: Must(NewFiltSMNLMS(4, 1, 1e-3, 0.01, nil))},
+		{name: "SM-AP", af: Must(NewFiltSMAP(4, 1, 2, 1e-3, 0.01, nil))},
+		{name: "Huber NLMS", af: Must(NewFiltHuberNLMS(4, 0.1, 1e-3, 0.05, nil))},
+		{name: "MCC LMS", af: Must(NewFiltMCCLMS(4, 0.1, 0.1, nil))},
+		{name: "Llncosh", af: Must(NewFiltLlncosh(4, 0.01, 3, nil))},
+		{name: "inverse QR-RLS", af: Must(NewFiltIQRRLS(4, 0.99, 0.1, nil))},
+		{name: "FTF", af: Must(NewFiltFTF(4, 0.99, 0.1, nil))},
+		{name: "LSL", af: Must(NewFiltLSL(4, 0.99, 0.1, nil))},
+		{name: "sliding-window RLS", af: Must(NewFiltSWRLS(4, 16, 0.1, nil))},
+		{name: "Kalman", af: Must(NewFiltKalman(4, 1e-4, 0.1, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.af.Clone()
+			c.Adapt(1, []float64{1, 2, 3, 4})
+			_, _, w := tt.af.GetParams()
+			if !reflect.DeepEqual(w, make([]float64, 4)) {
+				t.Errorf("Clone() adapting the clone changed the original weights to %v", w)
+			}
+			_, _, cw := c.GetParams()
+			if reflect.DeepEqual(cw, make([]float64, 4)) {
+				t.Errorf("Clone() the clone was not adapted")
+			}
+			c.Reset()
+			_, _, cw = c.GetParams()
+			if !reflect.DeepEqual(cw, make([]float64, 4)) {
+				t.Errorf("Reset() w = %v, want zeros", cw)
+			}
+		})
+	}
+}
+
+func TestAdaptiveFilter_Step(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.05)
+	tests := []struct {
+		name  string
+		newAF func() AdaptiveFilter
+	}{
+		{name: "LMS", newAF: func() AdaptiveFilter { return Must(NewFiltLMS(4, 0.1, nil)) }},
+		{name: "NLMS", newAF: func() AdaptiveFilter { return Must(NewFiltNLMS(4, 0.1, 1e-5, nil)) }},
+		{name: "RLS", newAF: func() AdaptiveFilter { return Must(NewFiltRLS(4, 0.99, 0.1, nil)) }},
+		{name: "AP", newAF: func() AdaptiveFilter { return Must(NewFiltAP(4, 0.1, 2, 1e-5, nil)) }},
+		{name: "SE-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltSELMS(4, 0.01, nil)) }},
+		{name: "SD-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltSDLMS(4, 0.01, nil)) }},
+		{name: "SS-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltSSLMS(4, 0.01, nil)) }},
+		{name: "LMF", newAF: func() AdaptiveFilter { return Must(NewFiltLMF(4, 0.01, nil)) }},
+		{name: "NLMF", newAF: func() AdaptiveFilter { return Must(NewFiltNLMF(4, 0.1, 1e-5, nil)) }},
+		{name: "VSS-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltVSSLMS(4, 0.1, 0.001, 0.1, 0.97, 1e-3, nil)) }},
+		{name: "Mathews", newAF: func() AdaptiveFilter { return Must(NewFiltMathewsLMS(4, 0.1, 0.001, 0.1, 1e-3, nil)) }},
+		{name: "Benveniste", newAF: func() AdaptiveFilter { return Must(NewFiltBenvenisteLMS(4, 0.1, 0.001, 0.1, 1e-3, nil)) }},
+		{name: "GNGD", newAF: func() AdaptiveFilter { return Must(NewFiltGNGD(4, 1, 1, 0.1, nil)) }},
+		{name: "PNLMS", newAF: func() AdaptiveFilter { return Must(NewFiltPNLMS(4, 0.5, 1e-3, 0.01, 0.01, nil)) }},
+		{name: "IPNLMS", newAF: func() AdaptiveFilter { return Must(NewFiltIPNLMS(4, 0.5, 1e-3, 0, nil)) }},
+		{name: "MPNLMS", newAF: func() AdaptiveFilter { return Must(NewFiltMPNLMS(4, 0.5, 1e-3, 0.01, 0.01, 1000, nil)) }},
+		{name: "ZA-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltZALMS(4, 0.1, 1e-4, nil)) }},
+		{name: "RZA-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltRZALMS(4, 0.1, 1e-4, 10, nil)) }},
+		{name: "l0-LMS", newAF: func() AdaptiveFilter { return Must(NewFiltL0LMS(4, 0.1, 1e-5, 10, nil)) }},
+		{name: "leaky LMS", newAF: func() AdaptiveFilter { return Must(NewFiltLeakyLMS(4, 0.1, 1e-3, nil)) }},
+		{name: "leaky NLMS", newAF: func() AdaptiveFilter { return Must(NewFiltLeakyNLMS(4, 0.1, 1e-3, 1e-3, nil)) }},
+		{name: "SM-NLMS", newAF: func() AdaptiveFilter { return Must(NewFiltSMNLMS(4, 1, 1e-3, 0.01, nil)) }},
+		{name: "SM-AP", newAF: func() AdaptiveFilter { return Must(NewFiltSMAP(4, 1, 2, 1e-3, 0.01, nil)) }},
+		{name: "Huber NLMS", newAF: func() AdaptiveFilter { return Must(NewFiltHuberNLMS(4, 0.1, 1e-3, 0.05, nil)) }},
+		{name: "MCC LMS", newAF: func() AdaptiveFilter { return Must(NewFiltMCCLMS(4, 0.1, 0.1, nil)) }},
+		{name: "Llncosh", newAF: func() AdaptiveFilter { return Must(NewFiltLlncosh(4, 0.01, 3, nil)) }},
+		{name: "inverse QR-RLS", newAF: func() AdaptiveFilter { return Must(NewFiltIQRRLS(4, 0.99, 0.1, nil)) }},
+		{name: "FTF", newAF: func() AdaptiveFilter { return Must(NewFiltFTF(4, 0.99, 0.1, nil)) }},
+		{name: "LSL", newAF: func() AdaptiveFilter { return Must(NewFiltLSL(4, 0.99, 0.1, nil)) }},
+		{name: "sliding-window RLS", newAF: func() AdaptiveFilter { return Must(NewFiltSWRLS(4, 16, 0.1, nil)) }},
+		{name: "Kalman", newAF: func() AdaptiveFilter { return Must(NewFiltKalman(4, 1e-4, 0.1, nil)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.newAF()
+			wantY, wantE, _, err := want.Run(d, x)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			af := tt.newAF()
+			for i := range d {
+				pred := af.Predict(x[i])
+				y, e := af.(Stepper).Step(d[i], x[i])
+				if y != wantY[i] || e != wantE[i] {
+					t.Fatalf("Step() sample %d = (%v, %v), want (%v, %v)", i, y, e, wantY[i], wantE[i])
+				}
+				if !floats.EqualWithinAbs(y, pred, 1e-12) {
+					t.Fatalf("Step() sample %d y = %v, want Predict() = %v", i, y, pred)
+				}
+			}
+			_, _, w := af.GetParams()
+			_, _, wantW := want.GetParams()
+			if !floats.Equal(w, wantW) {
+				t.Errorf("Step() w = %v, want %v", w, wantW)
+			}
+		})
+	}
+}
+
+func TestFiltRLS_Reset(t *testing.T) {
+	af := Must(NewFiltRLS(2, 0.99, 0.1, nil))
+	want := mat.DenseCopyOf(af.(*FiltRLS).rMat)
+	af.Adapt(1, []float64{1, 2})
+	af.Reset()
+	if got := af.(*FiltRLS).rMat; !mat.Equal(got, want) {
+		t.Errorf("Reset() rMat = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
+
+func TestFiltBase_SetWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		w       []float64
+		want    []float64
+		wantErr bool
+	}{
+		{name: "set", w: []float64{1, 2, 3, 4}, want: []float64{1, 2, 3, 4}},
+		{name: "nil", w: nil, want: []float64{0, 0, 0, 0}},
+		{name: "length mismatch", w: []float64{1, 2, 3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			af := Must(NewFiltLMS(4, 0.1, nil))
+			err := af.SetWeights(tt.w)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetWeights() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			_, _, w := af.GetParams()
+			if !reflect.DeepEqual(w, tt.want) {
+				t.Errorf("SetWeights() w = %v, want %v", w, tt.want)
+			}
+		})
+	}
+}
+
+func TestExploreLearningContext(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 256
+	L := 4
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	var x = make([][]float64, n)
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*0.05
+	}
+	af := Must(NewFiltNLMS(L, 0.1, 1e-5, nil))
+
+	want, wantMus, err := ExploreLearningContext(context.Background(), af, d, x, 0.01, 1.0, 20, 0.5, 2, "MSE", nil, 1)
+	if err != nil {
+		t.Fatalf("ExploreLearningContext() error = %v", err)
+	}
+	got, mus, err := ExploreLearningContext(context.Background(), af, d, x, 0.01, 1.0, 20, 0.5, 2, "MSE", nil, 4)
+	if err != nil {
+		t.Fatalf("ExploreLearningContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(mus, wantMus) {
+		t.Errorf("ExploreLearningContext() with 4 workers = %v, want %v", got, want)
+	}
+	if _, _, w := af.GetParams(); !reflect.DeepEqual(w, make([]float64, L)) {
+		t.Errorf("ExploreLearningContext() changed the weights of af to %v", w)
+	}
+
+	//misalignment against the true system
+	es, mus, err := ExploreLearningContext(context.Background(), af, d, x, 0.0001, 1.0, 20, 0.5, 2, "MSE", h, 4)
+	if err != nil {
+		t.Fatalf("ExploreLearningContext() error = %v", err)
+	}
+	if es[0] < floats.Dot(h, h)/float64(L)*0.9 {
+		t.Errorf("ExploreLearningContext() misalignment for mu = %v is %v, want close to the norm of targetW", mus[0], es[0])
+	}
+	if iMin := floats.MinIdx(es); es[iMin] > 1e-3 {
+		t.Errorf("ExploreLearningContext() smallest misalignment = %v, want < 1e-3", es[iMin])
+	}
+	if _, _, err := ExploreLearningContext(context.Background(), af, d, x, 0.01, 1.0, 2, 0.5, 1, "MSE", []float64{1}, 1); err == nil {
+		t.Errorf("ExploreLearningContext() with wrong length of targetW: error = nil, want error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := ExploreLearningContext(ctx, af, d, x, 0.01, 1.0, 20, 0.5, 2, "MSE", nil, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExploreLearningContext() with canceled context: error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFiltBase_runSteps_invalidRows(t *testing.T) {
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "SE-LMS", af: Must(NewFiltSELMS(4, 0.1, nil))},
+		{name: "LMS", af: Must(NewFiltLMS(4, 0.1, nil))},
+		{name: "NLMS", af: Must(NewFiltNLMS(4, 0.1, 1e-3, nil))},
+		{name: "RLS", af: Must(NewFiltRLS(4, 0.99, 0.1, nil))},
+		{name: "AP", af: Must(NewFiltAP(4, 0.1, 2, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := tt.af.Run([]float64{1}, [][]float64{{1, 2, 3}}); err == nil {
+				t.Errorf("Run() with rows of length 3 for n = 4: error = nil, want error")
+			}
+			if _, _, _, err := tt.af.Run(nil, nil); err != nil {
+				t.Errorf("Run() with no samples: error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestNewFilt_invalidLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		newAF func(n int) (AdaptiveFilter, error)
+	}{
+		{name: "LMS", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltLMS(n, 0.1, nil) }},
+		{name: "ZA-LMS", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltZALMS(n, 0.1, 1e-4, nil) }},
+		{name: "inverse QR-RLS", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltIQRRLS(n, 0.99, 0.1, nil) }},
+		{name: "FTF", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltFTF(n, 0.99, 0.1, nil) }},
+		{name: "LSL", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltLSL(n, 0.99, 0.1, nil) }},
+		{name: "sliding-window RLS", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltSWRLS(n, 16, 0.1, nil) }},
+		{name: "Kalman", newAF: func(n int) (AdaptiveFilter, error) { return NewFiltKalman(n, 1e-4, 0.1, nil) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, n := range []int{0, -1} {
+				if _, err := tt.newAF(n); err == nil {
+					t.Errorf("constructor with n = %d: error = nil, want error", n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package adf
+
+import (
+	"fmt"
+	"math/cmplx"
+
+	"github.com/pkg/errors"
+)
+
+//kind names of the complex filters.
+const (
+	kindCLMS  = "CLMS filter"
+	kindCNLMS = "CNLMS filter"
+	kindACLMS = "ACLMS filter"
+)
+
+//ComplexAdaptiveFilter is the interface of the adaptive filters working on complex128 samples.
+//It is the counterpart of AdaptiveFilter for baseband signals and frequency bins.
+//The output is y = w^T x and the error is e = d - y.
+type ComplexAdaptiveFilter interface {
+	//Predict calculates the new estimated value `y` from input slice `x`.
+	Predict(x []complex128) (y complex128)
+
+	//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+	//and update filter weights according to error `e`.
+	Adapt(d complex128, x []complex128)
+
+	//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+	//and update filter weights according to error `e`.
+	Step(d complex128, x []complex128) (y, e complex128)
+
+	//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+	//while updating filter weights according to error `e`.
+	//The weight history `wHist` is configured by `opts` in the same way as AdaptiveFilter.
+	Run(d []complex128, x [][]complex128, opts ...RunOption) (y []complex128, e []complex128, wHist [][]complex128, err error)
+
+	//GetParams returns the parameters at the time this func is called.
+	//parameters contains `n`: filter length, `mu`: filter update step size and `w`: filter weights.
+	GetParams() (int, float64, []complex128)
+
+	//GetKindName returns the name of ADF.
+	GetKindName() string
+
+	//SetWeights sets the filter weights. If `w` is nil, the weights are set to zeros.
+	//The slice `w` is copied.
+	SetWeights(w []complex128) error
+
+	//Reset sets the filter weights to zeros.
+	Reset()
+
+	//Clone returns a deep copy of the filter.
+	Clone() ComplexAdaptiveFilter
+}
+
+//MustComplex checks whether err is nil or not. If err in not nil, this func causes panic.
+func MustComplex(af ComplexAdaptiveFilter, err error) ComplexAdaptiveFilter {
+	if err != nil {
+		panic(err)
+	}
+	return af
+}
+
+//complexBase is base struct of the complex adaptive filters.
+type complexBase struct {
+	kind  string
+	n     int
+	muMin float64
+	muMax float64
+	mu    float64
+	//w is the weights. Its length is `n`, or `2n` for the widely-linear filters.
+	w []complex128
+}
+
+//init initialises the kind name, the filter length, the step size and the weights.
+func (af *complexBase) init(kind string, n int, mu float64, w []complex128) error {
+	af.kind = kind
+	af.n = n
+	af.muMin = 0
+	af.muMax = 2
+	if err := af.SetStepSize(mu); err != nil {
+		return err
+	}
+	if n <= 0 {
+		return fmt.Errorf("the filter length `n` must be positive. n: %d", n)
+	}
+	return af.initWeights(w, n)
+}
+
+//initWeights initialises the adaptive weights of the filter of length `m`.
+//If `w` is nil, this func initializes `w` as zeros.
+func (af *complexBase) initWeights(w []complex128, m int) error {
+	if w == nil {
+		w = make([]complex128, m)
+	}
+	if len(w) != m {
+		return fmt.Errorf("the length of slice `w` must be %d. len(w): %d", m, len(w))
+	}
+	af.w = w
+	return nil
+}
+
+//Predict calculates the new estimated value `y` from input slice `x`.
+func (af *complexBase) Predict(x []complex128) (y complex128) {
+	return cdot(af.w, x)
+}
+
+//SetWeights sets the filter weights. The length of `w` must be the filter length `n`.
+//If `w` is nil, the weights are set to zeros.
+//The slice `w` is copied.
+func (af *complexBase) SetWeights(w []complex128) error {
+	if w != nil {
+		w = append([]complex128{}, w...)
+	}
+	return af.initWeights(w, len(af.w))
+}
+
+//Reset sets the filter weights to zeros.
+func (af *complexBase) Reset() {
+	af.w = make([]complex128, len(af.w))
+}
+
+//SetStepSize set a update step size mu.
+func (af *complexBase) SetStepSize(mu float64) error {
+	mu, err := checkFloatParam(mu, af.muMin, af.muMax, "mu")
+	if err != nil {
+		return err
+	}
+	af.mu = mu
+	return nil
+}
+
+//GetParams returns the parameters at the time this func is called.
+//parameters contains `n`: filter length, `mu`: filter update step size and `w`: filter weights.
+func (af *complexBase) GetParams() (int, float64, []complex128) {
+	return af.n, af.mu, af.w
+}
+
+//GetKindName returns the name of ADF.
+func (af *complexBase) GetKindName() string {
+	return af.kind
+}
+
+//clone returns a deep copy of the base.
+func (af *complexBase) clone() complexBase {
+	altaf := *af
+	altaf.w = append([]complex128{}, af.w...)
+	return altaf
+}
+
+//run is the adaptation loop shared by the complex filters.
+//`step` is the Step method of the filter.
+func (af *complexBase) run(d []complex128, x [][]complex128, opts []RunOption, step func(d complex128, x []complex128) (y, e complex128)) (y []complex128, e []complex128, wHist [][]complex128, err error) {
+	//measure the data and check if the dimension agree
+	N := len(x)
+	if len(d) != N {
+		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+	}
+	if N > 0 && len(x[0]) != af.n {
+		return nil, nil, nil, fmt.Errorf("the length of rows of x and `n` must agree. len(x[0]): %d, n: %d", len(x[0]), af.n)
+	}
+	hist := newComplexHistory(N, len(af.w), opts)
+
+	y = make([]complex128, N)
+	e = make([]complex128, N)
+	//adaptation loop
+	for i := 0; i < N; i++ {
+		if err := hist.Record(i, af.w); err != nil {
+			return nil, nil, nil, err
+		}
+		y[i], e[i] = step(d[i], x[i])
+		hist.recordStepSize(i, af.mu)
+	}
+	return y, e, hist.Result(), nil
+}
+
+//FiltCLMS is base struct for complex LMS filter.
+//The weights are updated by w += mu * e * conj(x).
+//Use NewFiltCLMS to make instance.
+type FiltCLMS struct {
+	complexBase
+}
+
+//NewFiltCLMS is constructor of CLMS filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+func NewFiltCLMS(n int, mu float64, w []complex128) (ComplexAdaptiveFilter, error) {
+	p := new(FiltCLMS)
+	if err := p.init(kindCLMS, n, mu, w); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltCLMS) Adapt(d complex128, x []complex128) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltCLMS) Step(d complex128, x []complex128) (y, e complex128) {
+	y = cdot(af.w, x)
+	e = d - y
+	caxpyConj(complex(af.mu, 0)*e, x, af.w)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltCLMS) Run(d []complex128, x [][]complex128, opts ...RunOption) (y []complex128, e []complex128, wHist [][]complex128, err error) {
+	return af.run(d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltCLMS) Clone() ComplexAdaptiveFilter {
+	return &FiltCLMS{complexBase: af.clone()}
+}
+
+//FiltCNLMS is base struct for complex NLMS filter.
+//The weights are updated by w += mu / (eps + ||x||^2) * e * conj(x).
+//Use NewFiltCNLMS to make instance.
+type FiltCNLMS struct {
+	complexBase
+	eps float64
+}
+
+//NewFiltCNLMS is constructor of CNLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps` and filter weight `w`.
+func NewFiltCNLMS(n int, mu, eps float64, w []complex128) (ComplexAdaptiveFilter, error) {
+	p := new(FiltCNLMS)
+	if err := p.init(kindCNLMS, n, mu, w); err != nil {
+		return nil, err
+	}
+	//the same range as NewFiltNLMS
+	eps, err := checkFloatParam(eps, 0, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	p.eps = eps
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltCNLMS) Adapt(d complex128, x []complex128) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltCNLMS) Step(d complex128, x []complex128) (y, e complex128) {
+	y = cdot(af.w, x)
+	e = d - y
+	caxpyConj(complex(af.mu/(af.eps+cnorm2(x)), 0)*e, x, af.w)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltCNLMS) Run(d []complex128, x [][]complex128, opts ...RunOption) (y []complex128, e []complex128, wHist [][]complex128, err error) {
+	return af.run(d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltCNLMS) Clone() ComplexAdaptiveFilter {
+	return &FiltCNLMS{complexBase: af.clone(), eps: af.eps}
+}
+
+//FiltACLMS is base struct for augmented complex LMS filter, the widely-linear extension of CLMS.
+//The output is y = h^T x + g^T conj(x), which can model improper (noncircular) signals.
+//The weights are updated by h += mu * e * conj(x) and g += mu * e * x.
+//The weights `w` of GetParams, SetWeights and the history are the augmented weights [h; g] of length `2n`.
+//Use NewFiltACLMS to make instance.
+type FiltACLMS struct {
+	complexBase
+	//xa is the augmented input [x; conj(x)].
+	xa []complex128
+}
+
+//NewFiltACLMS is constructor of ACLMS filter.
+//This func initialize filter length `n`, update step size `mu` and augmented filter weight `w` of length `2n`.
+func NewFiltACLMS(n int, mu float64, w []complex128) (ComplexAdaptiveFilter, error) {
+	p := new(FiltACLMS)
+	if err := p.init(kindACLMS, n, mu, nil); err != nil {
+		return nil, err
+	}
+	if err := p.initWeights(w, 2*n); err != nil {
+		return nil, err
+	}
+	p.xa = make([]complex128, 2*n)
+	return p, nil
+}
+
+//augment sets the augmented input [x; conj(x)] to `af.xa`.
+func (af *FiltACLMS) augment(x []complex128) []complex128 {
+	copy(af.xa, x)
+	for i, v := range x {
+		af.xa[af.n+i] = cmplx.Conj(v)
+	}
+	return af.xa
+}
+
+//Predict calculates the new estimated value `y` from input slice `x`.
+func (af *FiltACLMS) Predict(x []complex128) (y complex128) {
+	return cdot(af.w, af.augment(x))
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltACLMS) Adapt(d complex128, x []complex128) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltACLMS) Step(d complex128, x []complex128) (y, e complex128) {
+	xa := af.augment(x)
+	y = cdot(af.w, xa)
+	e = d - y
+	caxpyConj(complex(af.mu, 0)*e, xa, af.w)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltACLMS) Run(d []complex128, x [][]complex128, opts ...RunOption) (y []complex128, e []complex128, wHist [][]complex128, err error) {
+	return af.run(d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltACLMS) Clone() ComplexAdaptiveFilter {
+	return &FiltACLMS{complexBase: af.clone(), xa: make([]complex128, len(af.xa))}
+}
+
+//cdot returns the unconjugated dot product w^T x.
+func cdot(w, x []complex128) (s complex128) {
+	for i, v := range x {
+		s += w[i] * v
+	}
+	return s
+}
+
+//caxpyConj adds `alpha*conj(x)` to `y` in place.
+func caxpyConj(alpha complex128, x, y []complex128) {
+	for i, v := range x {
+		y[i] += alpha * cmplx.Conj(v)
+	}
+}
+
+//cnorm2 returns the squared Euclidean norm of `x`.
+func cnorm2(x []complex128) (s float64) {
+	for _, v := range x {
+		s += real(v)*real(v) + imag(v)*imag(v)
+	}
+	return s
+}
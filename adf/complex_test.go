@@ -0,0 +1,267 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//newComplexSystemData makes the circular complex white input and the desired values of the widely-linear system
+//d = h^T x + g^T conj(x) with complex noise. If `g` is nil, the system is linear.
+func newComplexSystemData(n int, h, g []complex128, noise float64) (d []complex128, x [][]complex128) {
+	L := len(h)
+	x = make([][]complex128, n)
+	d = make([]complex128, n)
+	xRow := make([]complex128, L)
+	for i := 0; i < n; i++ {
+		copy(xRow, xRow[1:])
+		xRow[L-1] = complex(rand.NormFloat64(), rand.NormFloat64()) / math.Sqrt2
+		x[i] = append([]complex128{}, xRow...)
+		d[i] = cdot(h, x[i]) + complex(rand.NormFloat64(), rand.NormFloat64())*complex(noise, 0)
+		if g != nil {
+			for j, v := range x[i] {
+				d[i] += g[j] * cmplx.Conj(v)
+			}
+		}
+	}
+	return d, x
+}
+
+//complexMisalignment returns the normalized squared distance between `w` and `h` in dB.
+func complexMisalignment(w, h []complex128) float64 {
+	diff := make([]complex128, len(h))
+	for i := range h {
+		diff[i] = w[i] - h[i]
+	}
+	return 10 * math.Log10(cnorm2(diff)/cnorm2(h))
+}
+
+func TestComplexAdaptiveFilter_Run(t *testing.T) {
+	rand.Seed(1)
+	h := []complex128{0.5 + 0.2i, -0.3i, 0.1 - 0.1i, 0.05}
+	d, x := newComplexSystemData(4000, h, nil, 0.001)
+	tests := []struct {
+		name string
+		af   ComplexAdaptiveFilter
+	}{
+		{name: "CLMS", af: MustComplex(NewFiltCLMS(len(h), 0.05, nil))},
+		{name: "CNLMS", af: MustComplex(NewFiltCNLMS(len(h), 0.5, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y, e, wHist, err := tt.af.Run(d, x)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(y) != len(d) || len(e) != len(d) || len(wHist) != len(d) {
+				t.Fatalf("Run() len(y) = %d, len(e) = %d, len(wHist) = %d, want %d", len(y), len(e), len(wHist), len(d))
+			}
+			for i := range d {
+				if cmplx.Abs(y[i]+e[i]-d[i]) > 1e-12 {
+					t.Fatalf("Run() y[%d] + e[%d] = %v, want %v", i, i, y[i]+e[i], d[i])
+				}
+			}
+			_, _, w := tt.af.GetParams()
+			if m := complexMisalignment(w, h); m > -40 {
+				t.Errorf("%s misalignment = %.1f dB, want < -40 dB", tt.name, m)
+			}
+		})
+	}
+}
+
+func TestFiltACLMS_improper(t *testing.T) {
+	rand.Seed(1)
+	h := []complex128{0.5 + 0.2i, -0.3i, 0.1 - 0.1i, 0.05}
+	g := []complex128{0.2, 0.1 + 0.1i, 0, -0.05i}
+	d, x := newComplexSystemData(4000, h, g, 0.001)
+
+	aclms := MustComplex(NewFiltACLMS(len(h), 0.05, nil))
+	_, e, _, err := aclms.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, w := aclms.GetParams()
+	if len(w) != 2*len(h) {
+		t.Fatalf("GetParams() len(w) = %d, want %d", len(w), 2*len(h))
+	}
+	if m := complexMisalignment(w, append(append([]complex128{}, h...), g...)); m > -40 {
+		t.Errorf("ACLMS misalignment = %.1f dB, want < -40 dB", m)
+	}
+
+	//the strictly linear filter cannot model the conjugate part
+	clms := MustComplex(NewFiltCLMS(len(h), 0.05, nil))
+	_, eCLMS, _, err := clms.Run(d, x, WithoutHistory())
+	check(err)
+	var mse, mseCLMS float64
+	for i := len(d) - 1000; i < len(d); i++ {
+		mse += real(e[i] * cmplx.Conj(e[i]))
+		mseCLMS += real(eCLMS[i] * cmplx.Conj(eCLMS[i]))
+	}
+	if mse*100 > mseCLMS {
+		t.Errorf("ACLMS MSE = %v, want 20 dB lower than CLMS MSE = %v", mse/1000, mseCLMS/1000)
+	}
+}
+
+func TestFiltCLMS_real(t *testing.T) {
+	rand.Seed(1)
+	dr, xr := newSystemData(256, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	d := make([]complex128, len(dr))
+	x := make([][]complex128, len(xr))
+	for i := range dr {
+		d[i] = complex(dr[i], 0)
+		x[i] = make([]complex128, len(xr[i]))
+		for j, v := range xr[i] {
+			x[i][j] = complex(v, 0)
+		}
+	}
+	//with real signals, CLMS and CNLMS are LMS and NLMS
+	tests := []struct {
+		name string
+		af   ComplexAdaptiveFilter
+		want AdaptiveFilter
+	}{
+		{name: "CLMS", af: MustComplex(NewFiltCLMS(4, 0.1, nil)), want: Must(NewFiltLMS(4, 0.1, nil))},
+		{name: "CNLMS", af: MustComplex(NewFiltCNLMS(4, 0.5, 1e-3, nil)), want: Must(NewFiltNLMS(4, 0.5, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, e, _, err := tt.af.Run(d, x)
+			check(err)
+			_, wantE, _, err := tt.want.Run(dr, xr)
+			check(err)
+			for i := range e {
+				if imag(e[i]) != 0 || !floats.EqualWithinAbs(real(e[i]), wantE[i], 1e-12) {
+					t.Fatalf("Run() e[%d] = %v, want %v", i, e[i], wantE[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComplexAdaptiveFilter_Step(t *testing.T) {
+	rand.Seed(1)
+	d, x := newComplexSystemData(64, []complex128{0.5, -0.3i, 0.1, 0.05}, nil, 0.01)
+	tests := []struct {
+		name  string
+		newAF func() ComplexAdaptiveFilter
+	}{
+		{name: "CLMS", newAF: func() ComplexAdaptiveFilter { return MustComplex(NewFiltCLMS(4, 0.05, nil)) }},
+		{name: "CNLMS", newAF: func() ComplexAdaptiveFilter { return MustComplex(NewFiltCNLMS(4, 0.5, 1e-3, nil)) }},
+		{name: "ACLMS", newAF: func() ComplexAdaptiveFilter { return MustComplex(NewFiltACLMS(4, 0.05, nil)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.newAF()
+			wantY, wantE, _, err := want.Run(d, x)
+			check(err)
+			af := tt.newAF()
+			pre := tt.newAF()
+			for i := range d {
+				if p := pre.Predict(x[i]); p != wantY[i] {
+					t.Fatalf("Predict() = %v, want %v", p, wantY[i])
+				}
+				pre.Adapt(d[i], x[i])
+				y, e := af.Step(d[i], x[i])
+				if y != wantY[i] || e != wantE[i] {
+					t.Fatalf("Step() = (%v, %v), want (%v, %v)", y, e, wantY[i], wantE[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComplexAdaptiveFilter_Clone(t *testing.T) {
+	tests := []struct {
+		name string
+		af   ComplexAdaptiveFilter
+	}{
+		{name: "CLMS", af: MustComplex(NewFiltCLMS(4, 0.1, nil))},
+		{name: "CNLMS", af: MustComplex(NewFiltCNLMS(4, 0.1, 1e-3, nil))},
+		{name: "ACLMS", af: MustComplex(NewFiltACLMS(4, 0.1, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, w0 := tt.af.GetParams()
+			want := append([]complex128{}, w0...)
+			c := tt.af.Clone()
+			c.Adapt(1i, []complex128{1, 2i, 3, 4})
+			_, _, w := tt.af.GetParams()
+			if !reflect.DeepEqual(w, want) {
+				t.Errorf("Clone() adapting the clone changed the original weights to %v", w)
+			}
+			if c.GetKindName() != tt.af.GetKindName() {
+				t.Errorf("Clone() kind = %v, want %v", c.GetKindName(), tt.af.GetKindName())
+			}
+		})
+	}
+}
+
+func TestComplexAdaptiveFilter_Run_history(t *testing.T) {
+	rand.Seed(1)
+	d, x := newComplexSystemData(10, []complex128{0.5, -0.3i}, nil, 0.01)
+	_, _, full, err := MustComplex(NewFiltCLMS(2, 0.1, nil)).Run(d, x)
+	check(err)
+
+	_, _, every, err := MustComplex(NewFiltCLMS(2, 0.1, nil)).Run(d, x, WithHistoryEvery(4))
+	check(err)
+	if want := [][]complex128{full[0], full[4], full[8]}; !reflect.DeepEqual(every, want) {
+		t.Errorf("Run() with WithHistoryEvery wHist = %v, want %v", every, want)
+	}
+
+	var streamed [][]float64
+	_, _, wHist, err := MustComplex(NewFiltCLMS(2, 0.1, nil)).Run(d, x, WithHistoryFunc(func(i int, w []float64) error {
+		streamed = append(streamed, append([]float64{}, w...))
+		return nil
+	}))
+	check(err)
+	if wHist != nil {
+		t.Errorf("Run() with WithHistoryFunc wHist = %v, want nil", wHist)
+	}
+	for i, w := range full {
+		//the real and imaginary parts are interleaved
+		want := []float64{real(w[0]), imag(w[0]), real(w[1]), imag(w[1])}
+		if !reflect.DeepEqual(streamed[i], want) {
+			t.Fatalf("WithHistoryFunc w = %v, want %v", streamed[i], want)
+		}
+	}
+}
+
+func TestNewFiltComplex_invalidParams(t *testing.T) {
+	if _, err := NewFiltCLMS(4, 3, nil); err == nil {
+		t.Errorf("NewFiltCLMS() with mu = 3: error = nil, want error")
+	}
+	if _, err := NewFiltCNLMS(4, 0.5, 2, nil); err == nil {
+		t.Errorf("NewFiltCNLMS() with eps = 2: error = nil, want error")
+	}
+	if _, err := NewFiltCLMS(0, 0.1, nil); err == nil {
+		t.Errorf("NewFiltCLMS() with n = 0: error = nil, want error")
+	}
+	//ACLMS takes the augmented weights of length 2n
+	if _, err := NewFiltACLMS(4, 0.1, make([]complex128, 4)); err == nil {
+		t.Errorf("NewFiltACLMS() with len(w) = 4: error = nil, want error")
+	}
+	if _, err := NewFiltACLMS(4, 0.1, make([]complex128, 8)); err != nil {
+		t.Errorf("NewFiltACLMS() with len(w) = 8: error = %v", err)
+	}
+}
+
+func ExampleFiltCNLMS_Run() {
+	rand.Seed(1)
+	h := []complex128{0.5 + 0.2i, -0.3i, 0.1 - 0.1i, 0.05}
+	d, x := newComplexSystemData(1000, h, nil, 0.001)
+	af := MustComplex(NewFiltCNLMS(len(h), 0.5, 1e-3, nil))
+	_, _, _, err := af.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, w := af.GetParams()
+	for _, v := range w {
+		fmt.Printf("%.2f\n", v)
+	}
+	//output:
+	//(0.50+0.20i)
+	//(-0.00-0.30i)
+	//(0.10-0.10i)
+	//(0.05-0.00i)
+}
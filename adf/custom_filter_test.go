@@ -0,0 +1,107 @@
+package adf_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/adf"
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+//signLMS is an adaptive filter implemented outside of package adf.
+type signLMS struct {
+	mu float64
+	w  []float64
+}
+
+func (af *signLMS) Predict(x []float64) float64 {
+	return floats.Dot(af.w, x)
+}
+
+func (af *signLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+func (af *signLMS) Step(d float64, x []float64) (float64, float64) {
+	y := af.Predict(x)
+	e := d - y
+	s := 1.0
+	if e < 0 {
+		s = -1.0
+	}
+	floats.AddScaled(af.w, af.mu*s, x)
+	return y, e
+}
+
+func (af *signLMS) Run(d []float64, x [][]float64, opts ...adf.RunOption) ([]float64, []float64, [][]float64, error) {
+	if len(d) != len(x) {
+		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+	}
+	y := make([]float64, len(d))
+	e := make([]float64, len(d))
+	wHist := make([][]float64, len(d))
+	for i := range d {
+		wHist[i] = append([]float64{}, af.w...)
+		y[i], e[i] = af.Step(d[i], x[i])
+	}
+	return y, e, wHist, nil
+}
+
+func (af *signLMS) GetParams() (int, float64, []float64) { return len(af.w), af.mu, af.w }
+
+func (af *signLMS) GetKindName() string { return "sign LMS filter" }
+
+func (af *signLMS) SetWeights(w []float64) error {
+	if w == nil {
+		w = make([]float64, len(af.w))
+	}
+	if len(w) != len(af.w) {
+		return errors.New("the length of slice w and n must agree")
+	}
+	copy(af.w, w)
+	return nil
+}
+
+func (af *signLMS) Reset() {
+	for i := range af.w {
+		af.w[i] = 0
+	}
+}
+
+func (af *signLMS) Clone() adf.AdaptiveFilter {
+	return &signLMS{mu: af.mu, w: append([]float64{}, af.w...)}
+}
+
+func (af *signLMS) SetStepSize(mu float64) error {
+	af.mu = mu
+	return nil
+}
+
+func TestAdaptiveFilter_userDefined(t *testing.T) {
+	rand.Seed(1)
+	n := 256
+	L := 4
+	var x = make([][]float64, n)
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = x[i][L-1]
+	}
+	var af adf.AdaptiveFilter = &signLMS{mu: 0.01, w: make([]float64, L)}
+
+	if _, _, _, err := adf.PreTrainedRun(af, d, x, 0.5, 2); err != nil {
+		t.Fatalf("PreTrainedRun() error = %v", err)
+	}
+	es, mus, err := adf.ExploreLearning(af, d, x, 0.001, 0.1, 10, 0.5, 2, "MSE", nil)
+	if err != nil {
+		t.Fatalf("ExploreLearning() error = %v", err)
+	}
+	if len(es) != 10 || len(mus) != 10 {
+		t.Errorf("ExploreLearning() len(es) = %d, len(mus) = %d, want 10", len(es), len(mus))
+	}
+}
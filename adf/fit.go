@@ -0,0 +1,133 @@
+package adf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+//FitConfig is the configuration of Fit.
+type FitConfig struct {
+	//NTrain is train to validation ratio, typical value is 0.5. (that means 50% of data is used for training)
+	//The rest of the data is used for validation.
+	NTrain float64
+	//Epochs is the maximum number of training epochs.
+	Epochs int
+	//Criteria is how should be measured the mean error. Available values are "MSE", "MAE" and "RMSE".
+	Criteria string
+	//Patience is the number of epochs without improvement of the validation error before the training stops.
+	//If it is 0, the training runs for all epochs.
+	Patience int
+	//MinDelta is the minimum decrease of the validation error which counts as improvement.
+	MinDelta float64
+}
+
+//EpochRecord is the learning record of an epoch.
+type EpochRecord struct {
+	//Epoch is the index of the epoch starting from 0.
+	Epoch int
+	//TrainError is the mean error of the adaptation on the training data.
+	TrainError float64
+	//ValidError is the mean error of the prediction on the validation data with the weights after the epoch.
+	ValidError float64
+}
+
+//FitResult is the result of Fit.
+type FitResult struct {
+	//W is the weights after the epoch with the smallest validation error.
+	W []float64
+	//BestEpoch is the epoch with the smallest validation error.
+	BestEpoch int
+	//BestError is the smallest validation error.
+	BestError float64
+	//History is the learning record of every epoch run.
+	History []EpochRecord
+	//EarlyStopped reports whether the training stopped before `Epochs` because the validation error did not improve.
+	EarlyStopped bool
+}
+
+//Fit trains the adaptive filter `af` offline with the desired values `d` and input matrix `x`.
+//
+//The first part of the data is used for training and the rest is used for validation.
+//In every epoch the filter is adapted on the training data,
+//and then the validation data is predicted without adaptation.
+//The training stops early when the validation error does not improve for `Patience` epochs.
+//
+//After the training, the weights of `af` are set to the best weights.
+//Other internal state of `af`, such as the inverse correlation matrix of RLS, is kept as it is after the last epoch.
+func Fit(af AdaptiveFilter, d []float64, x [][]float64, cfg FitConfig) (*FitResult, error) {
+	return FitContext(context.Background(), af, d, x, cfg)
+}
+
+//FitContext is Fit with cancellation. When `ctx` is done, the training stops and the error of `ctx` is returned.
+func FitContext(ctx context.Context, af AdaptiveFilter, d []float64, x [][]float64, cfg FitConfig) (*FitResult, error) {
+	N := len(x)
+	if len(d) != N {
+		return nil, errors.New("the length of slice d and x must agree")
+	}
+	if cfg.Epochs < 1 {
+		return nil, fmt.Errorf("the number of epochs must be positive. Epochs: %d", cfg.Epochs)
+	}
+	nTrainI := int(float64(N) * cfg.NTrain)
+	if nTrainI < 1 || nTrainI >= N {
+		return nil, fmt.Errorf("NTrain must leave samples for both training and validation. NTrain: %v, N: %d", cfg.NTrain, N)
+	}
+	dTrain, xTrain := d[:nTrainI], x[:nTrainI]
+	dValid, xValid := d[nTrainI:], x[nTrainI:]
+
+	res := &FitResult{
+		BestEpoch: -1,
+		BestError: math.Inf(1),
+		History:   make([]EpochRecord, 0, cfg.Epochs),
+	}
+	eValid := make([]float64, len(dValid))
+	zeros := make([]float64, len(dValid))
+	sinceBest := 0
+	for epoch := 0; epoch < cfg.Epochs; epoch++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		//train
+		_, eTrain, _, err := af.Run(dTrain, xTrain, WithoutHistory())
+		if err != nil {
+			return nil, err
+		}
+		trainErr, err := misc.GetMeanError(eTrain, make([]float64, len(eTrain)), cfg.Criteria)
+		if err != nil {
+			return nil, err
+		}
+		//validate
+		for i := range dValid {
+			eValid[i] = dValid[i] - af.Predict(xValid[i])
+		}
+		validErr, err := misc.GetMeanError(eValid, zeros, cfg.Criteria)
+		if err != nil {
+			return nil, err
+		}
+		res.History = append(res.History, EpochRecord{Epoch: epoch, TrainError: trainErr, ValidError: validErr})
+
+		if validErr < res.BestError-cfg.MinDelta {
+			_, _, w := af.GetParams()
+			res.W = append(res.W[:0], w...)
+			res.BestEpoch = epoch
+			res.BestError = validErr
+			sinceBest = 0
+		} else {
+			sinceBest++
+			if cfg.Patience > 0 && sinceBest >= cfg.Patience {
+				res.EarlyStopped = epoch < cfg.Epochs-1
+				break
+			}
+		}
+	}
+	if res.W == nil {
+		return nil, errors.New("the validation error is not a number in any epoch")
+	}
+	if err := af.SetWeights(res.W); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,120 @@
+package adf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+//newSystemData makes the input matrix `x` and the desired values `d` of an unknown FIR system `h` with noise.
+func newSystemData(n int, h []float64, noise float64) (d []float64, x [][]float64) {
+	L := len(h)
+	x = make([][]float64, n)
+	d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*noise
+	}
+	return d, x
+}
+
+func TestFit(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(512, h, 0.05)
+
+	tests := []struct {
+		name          string
+		cfg           FitConfig
+		wantEpochs    int
+		wantEarlyStop bool
+	}{
+		{
+			name:       "all epochs",
+			cfg:        FitConfig{NTrain: 0.5, Epochs: 5, Criteria: "MSE"},
+			wantEpochs: 5,
+		},
+		{
+			name:          "early stopping",
+			cfg:           FitConfig{NTrain: 0.5, Epochs: 100, Criteria: "MSE", Patience: 2, MinDelta: 1e-4},
+			wantEarlyStop: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			af := Must(NewFiltNLMS(len(h), 0.5, 1e-5, nil))
+			res, err := Fit(af, d, x, tt.cfg)
+			if err != nil {
+				t.Fatalf("Fit() error = %v", err)
+			}
+			if tt.wantEpochs > 0 && len(res.History) != tt.wantEpochs {
+				t.Errorf("Fit() len(History) = %d, want %d", len(res.History), tt.wantEpochs)
+			}
+			if res.EarlyStopped != tt.wantEarlyStop {
+				t.Errorf("Fit() EarlyStopped = %v, want %v", res.EarlyStopped, tt.wantEarlyStop)
+			}
+			if tt.wantEarlyStop && len(res.History) != res.BestEpoch+1+tt.cfg.Patience {
+				t.Errorf("Fit() stopped after %d epochs, want %d", len(res.History), res.BestEpoch+1+tt.cfg.Patience)
+			}
+			for _, r := range res.History {
+				if r.ValidError < res.BestError-tt.cfg.MinDelta {
+					t.Errorf("Fit() BestError = %v, but epoch %d has %v", res.BestError, r.Epoch, r.ValidError)
+				}
+			}
+			if res.History[res.BestEpoch].ValidError != res.BestError {
+				t.Errorf("Fit() BestError = %v, want %v", res.BestError, res.History[res.BestEpoch].ValidError)
+			}
+			if !floats.EqualApprox(res.W, h, 0.05) {
+				t.Errorf("Fit() W = %v, want %v", res.W, h)
+			}
+			if _, _, w := af.GetParams(); !floats.Equal(w, res.W) {
+				t.Errorf("Fit() weights of af = %v, want the best weights %v", w, res.W)
+			}
+		})
+	}
+}
+
+func TestFit_invalidConfig(t *testing.T) {
+	d, x := newSystemData(16, []float64{1, 0}, 0)
+	tests := []struct {
+		name string
+		d    []float64
+		cfg  FitConfig
+	}{
+		{name: "no epochs", d: d, cfg: FitConfig{NTrain: 0.5, Epochs: 0, Criteria: "MSE"}},
+		{name: "no validation data", d: d, cfg: FitConfig{NTrain: 1, Epochs: 1, Criteria: "MSE"}},
+		{name: "no training data", d: d, cfg: FitConfig{NTrain: 0, Epochs: 1, Criteria: "MSE"}},
+		{name: "unknown criteria", d: d, cfg: FitConfig{NTrain: 0.5, Epochs: 1, Criteria: "MSLE"}},
+		{name: "length mismatch", d: d[1:], cfg: FitConfig{NTrain: 0.5, Epochs: 1, Criteria: "MSE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Fit(Must(NewFiltLMS(2, 0.1, nil)), tt.d, x, tt.cfg); err == nil {
+				t.Errorf("Fit() error = nil, want error")
+			}
+		})
+	}
+}
+
+func ExampleFit() {
+	rand.Seed(1)
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(512, h, 0.05)
+
+	af := Must(NewFiltLMS(len(h), 0.01, nil))
+	res, err := Fit(af, d, x, FitConfig{NTrain: 0.5, Epochs: 50, Criteria: "MSE", Patience: 3, MinDelta: 1e-5})
+	check(err)
+
+	fmt.Printf("best epoch: %d, validation MSE: %.4f, early stopped: %v\n", res.BestEpoch, res.BestError, res.EarlyStopped)
+	fmt.Printf("w: %.2f\n", res.W)
+	//output:
+	//best epoch: 1, validation MSE: 0.0027, early stopped: true
+	//w: [0.39 -0.20 0.10 0.05]
+}
@@ -0,0 +1,206 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindFTF is the kind name of FiltFTF.
+const kindFTF = "FTF filter"
+
+//constants of the stabilized FTF algorithm by Slock and Kailath.
+//The backward prediction error is a mixture of its two computations weighted by these constants.
+const (
+	ftfKappa1 = 1.5
+	ftfKappa2 = 2.5
+	ftfKappa3 = 1.0
+)
+
+//FiltFTF is base struct for fast transversal filter (FTF), the RLS algorithm with O(n) complexity.
+//It converges like FiltRLS, but updates the forward and backward predictors of the input
+//instead of the inverse correlation matrix.
+//This is the stabilized FTF. When the conversion factor leaves (0, 1] or the prediction error energies
+//are not positive, the predictors are reinitialized (rescue). The filter weights are kept on the rescue.
+//Because the algorithm exploits the shift structure of the input,
+//the rows of `x` must be the tap-delay line, that is x[i+1] is x[i] shifted by one sample.
+//Use NewFiltFTF to make instance.
+type FiltFTF struct {
+	filtBase
+	eps float64
+	//wf and wb are the forward and backward predictors.
+	wf []float64
+	wb []float64
+	//phi is the normalized gain vector and phiExt is the workspace for the extended gain vector.
+	phi    []float64
+	phiExt []float64
+	//gamma is the conversion factor.
+	gamma float64
+	//xif and xib are the minimum forward and backward prediction error energies.
+	xif float64
+	xib float64
+	//prev is the input of the previous sample.
+	prev []float64
+	//pow is the exponentially weighted energy of the input, which is the prediction error energies on the rescue.
+	pow     float64
+	rescues int
+}
+
+//NewFiltFTF is constructor of FTF filter.
+//This func initialize filter length `n`, forgetting factor `mu`, the initial prediction error energy `eps`,
+//and filter weight `w`. `mu` and `eps` correspond to the parameters of NewFiltRLS.
+//FTF needs `mu` closer to 1 than RLS, about 1 - 1/(2n) or larger, otherwise the rescues occur frequently.
+func NewFiltFTF(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltFTF)
+	p.kind = kindFTF
+	p.n = n
+	p.muMin = math.SmallestNonzeroFloat64
+	p.muMax = 1
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, math.SmallestNonzeroFloat64, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.wf = make([]float64, n)
+	p.wb = make([]float64, n)
+	p.phi = make([]float64, n)
+	p.phiExt = make([]float64, n+1)
+	p.prev = make([]float64, n)
+	p.initPredictors(eps)
+	return p, nil
+}
+
+//Rescues returns the number of the rescues, that is the reinitializations of the predictors.
+//A growing count indicates numerical trouble, e.g. a forgetting factor too small for the filter length.
+func (af *FiltFTF) Rescues() int {
+	return af.rescues
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltFTF) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+//
+//The vectors of the predictors and the gain are ordered from the newest sample,
+//while the weights `w` are ordered as `x`, from the oldest sample.
+func (af *FiltFTF) Step(d float64, x []float64) (y, e float64) {
+	n := af.n
+	lam := af.mu
+	wf, wb, phi, ext := af.wf, af.wb, af.phi, af.phiExt
+
+	//forward prediction of x[n-1] from the previous input
+	ef := x[n-1]
+	for i := 0; i < n; i++ {
+		ef -= wf[i] * af.prev[n-1-i]
+	}
+	epsf := ef * af.gamma
+	c := ef / (lam * af.xif)
+	ext[0] = c
+	for i := 1; i <= n; i++ {
+		ext[i] = phi[i-1] - c*wf[i-1]
+	}
+	gammaExt := 1 / (1/af.gamma + ext[0]*ef)
+	af.xif = lam*af.xif + ef*epsf
+	floats.AddScaled(wf, epsf, phi)
+
+	//backward prediction of the sample which leaves the input
+	eb1 := lam * af.xib * ext[n]
+	eb2 := af.prev[0]
+	for i := 0; i < n; i++ {
+		eb2 -= wb[i] * x[n-1-i]
+	}
+	ebK1 := ftfKappa1*eb2 + (1-ftfKappa1)*eb1
+	ebK2 := ftfKappa2*eb2 + (1-ftfKappa2)*eb1
+	ebK3 := ftfKappa3*eb2 + (1-ftfKappa3)*eb1
+	af.gamma = 1 / (1/gammaExt - ext[n]*ebK3)
+	af.xib = lam*af.xib + af.gamma*ebK2*ebK2
+	for i := 0; i < n; i++ {
+		phi[i] = ext[i] + ext[n]*wb[i]
+	}
+	floats.AddScaled(wb, af.gamma*ebK1, phi)
+
+	copy(af.prev, x)
+	af.pow = lam*af.pow + x[n-1]*x[n-1]
+	//rescue before the invalid gain reaches the weights
+	if !(0 < af.gamma && af.gamma <= 1 && af.xif > 0 && af.xib > 0) {
+		af.rescues++
+		af.initPredictors(math.Max(af.pow, af.eps))
+	}
+
+	//joint process estimation
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	epsilon := e * af.gamma
+	for j := 0; j < n; j++ {
+		w[j] += phi[n-1-j] * epsilon
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltFTF) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//SetStepSize sets the forgetting factor mu.
+//The prediction error energies depend on mu, so the predictors are reinitialized as on the rescue.
+//The filter weights are kept.
+func (af *FiltFTF) SetStepSize(mu float64) error {
+	if err := af.filtBase.SetStepSize(mu); err != nil {
+		return err
+	}
+	af.initPredictors(math.Max(af.pow, af.eps))
+	return nil
+}
+
+//Reset sets the filter weights to zeros, initializes the predictors and clears the rescue count.
+func (af *FiltFTF) Reset() {
+	af.filtBase.Reset()
+	for i := range af.prev {
+		af.prev[i] = 0
+	}
+	af.pow = 0
+	af.rescues = 0
+	af.initPredictors(af.eps)
+}
+
+//initPredictors initializes the predictors, the gain vector and the conversion factor,
+//and sets the forward prediction error energy to `xi`.
+//The backward prediction error energy is xi / mu^n, which is consistent with the soft-constrained start.
+func (af *FiltFTF) initPredictors(xi float64) {
+	for i := 0; i < af.n; i++ {
+		af.wf[i] = 0
+		af.wb[i] = 0
+		af.phi[i] = 0
+	}
+	af.gamma = 1
+	af.xif = xi
+	af.xib = xi * math.Pow(af.mu, -float64(af.n))
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltFTF) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.wf = append([]float64{}, af.wf...)
+	altaf.wb = append([]float64{}, af.wb...)
+	altaf.phi = append([]float64{}, af.phi...)
+	altaf.phiExt = make([]float64, len(af.phiExt))
+	altaf.prev = append([]float64{}, af.prev...)
+	return &altaf
+}
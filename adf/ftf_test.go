@@ -0,0 +1,123 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestFiltFTF_agreesWithRLS(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	d, x := newSystemData(4000, h, 0.01)
+	af := Must(NewFiltFTF(len(h), 0.99, 0.01, nil))
+	want := Must(NewFiltIQRRLS(len(h), 0.99, 0.01, nil))
+	_, e, wHist, err := af.Run(d, x)
+	check(err)
+	_, wantE, wantWHist, err := want.Run(d, x)
+	check(err)
+	//the initialization differs, but its effect is forgotten
+	for i := 2000; i < len(d); i++ {
+		if !floats.EqualWithinAbs(e[i], wantE[i], 1e-9) {
+			t.Fatalf("Run() e[%d] = %v, want %v", i, e[i], wantE[i])
+		}
+		if !floats.EqualApprox(wHist[i], wantWHist[i], 1e-9) {
+			t.Fatalf("Run() wHist[%d] = %v, want %v", i, wHist[i], wantWHist[i])
+		}
+	}
+	if r := af.(*FiltFTF).Rescues(); r != 0 {
+		t.Errorf("Rescues() = %d, want 0", r)
+	}
+}
+
+func TestFiltFTF_longFilter(t *testing.T) {
+	rand.Seed(1)
+	L := 512
+	h := make([]float64, L)
+	for i := range h {
+		h[i] = rand.NormFloat64() * math.Exp(-float64(i)/64)
+	}
+	d, x := newSystemData(8*L, h, 1e-3)
+	af := Must(NewFiltFTF(L, 1-1/float64(4*L), 0.01, nil))
+	_, _, _, err := af.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, w := af.GetParams()
+	if m := misalignment(w, h); m > -30 {
+		t.Errorf("misalignment = %.1f dB, want < -30 dB", m)
+	}
+	if r := af.(*FiltFTF).Rescues(); r != 0 {
+		t.Errorf("Rescues() = %d, want 0", r)
+	}
+}
+
+func TestFiltFTF_Rescues(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	d, x := newSystemData(4000, h, 0.01)
+	//the forgetting factor is too small for the filter length and the predictors become invalid
+	af := Must(NewFiltFTF(len(h), 0.8, 0.01, nil))
+	_, e, _, err := af.Run(d, x, WithoutHistory())
+	check(err)
+	ftf := af.(*FiltFTF)
+	if ftf.Rescues() == 0 {
+		t.Fatalf("Rescues() = 0, want rescues")
+	}
+	//the rescues keep the filter from diverging
+	for i, v := range e {
+		if math.IsNaN(v) || math.Abs(v) > 100 {
+			t.Fatalf("Run() e[%d] = %v, want bounded errors", i, v)
+		}
+	}
+	af.Reset()
+	if ftf.Rescues() != 0 {
+		t.Errorf("Rescues() after Reset() = %d, want 0", ftf.Rescues())
+	}
+}
+
+func TestFiltFTF_SetStepSize(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(2000, h, 0.001)
+	af := Must(NewFiltFTF(len(h), 0.99, 0.01, nil))
+	_, _, _, err := af.Run(d[:1000], x[:1000], WithoutHistory())
+	check(err)
+	//the predictors are reinitialized for the new forgetting factor and the weights are kept
+	check(af.(StepSizeSetter).SetStepSize(0.999))
+	_, _, w := af.GetParams()
+	if !floats.EqualApprox(w, h, 0.01) {
+		t.Fatalf("w after SetStepSize() = %v, want %v", w, h)
+	}
+	_, e, _, err := af.Run(d[1000:], x[1000:], WithoutHistory())
+	check(err)
+	for i, v := range e {
+		if math.IsNaN(v) || math.Abs(v) > 0.1 {
+			t.Fatalf("Run() after SetStepSize() e[%d] = %v, want small errors", i, v)
+		}
+	}
+	if err := af.(StepSizeSetter).SetStepSize(0); err == nil {
+		t.Errorf("SetStepSize(0) error = nil, want error")
+	}
+	if _, err := NewFiltFTF(4, 0, 0.01, nil); err == nil {
+		t.Errorf("NewFiltFTF() with mu = 0: error = nil, want error")
+	}
+}
+
+func BenchmarkFiltFTF_Adapt(b *testing.B) {
+	for _, L := range []int{16, 64, 256, 512} {
+		b.Run(fmt.Sprintf("n=%d", L), func(b *testing.B) {
+			rand.Seed(1)
+			xs := misc.NewNormRandSlice(L + 1024)
+			af := Must(NewFiltFTF(L, 0.999, 0.1, nil))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				k := i % 1024
+				af.Adapt(xs[k+L-1], xs[k:k+L])
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package adf
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Float is the constraint of the sample type of the generic adaptive filters.
+type Float interface {
+	~float32 | ~float64
+}
+
+//kind names of the generic filters.
+const (
+	kindGenericLMS  = "generic LMS filter"
+	kindGenericNLMS = "generic NLMS filter"
+)
+
+//genericBase is base struct of the generic adaptive filters working in float32 or float64.
+//The weights are kept in a plain slice of `T`.
+type genericBase[T Float] struct {
+	kind  string
+	n     int
+	muMin float64
+	muMax float64
+	mu    T
+	w     []T
+}
+
+//init initialises the kind name, the filter length, the step size and the weights.
+func (af *genericBase[T]) init(kind string, n int, mu T, w []T) error {
+	af.kind = kind
+	af.n = n
+	af.muMin = 0
+	af.muMax = 2
+	if err := af.SetStepSize(mu); err != nil {
+		return err
+	}
+	return af.initWeights(w, n)
+}
+
+//initWeights initialises the adaptive weights of the filter.
+//If `w` is nil, this func initializes `w` as zeros.
+func (af *genericBase[T]) initWeights(w []T, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("the filter length `n` must be positive. n: %d", n)
+	}
+	if w == nil {
+		w = make([]T, n)
+	}
+	if len(w) != n {
+		return fmt.Errorf("the length of slice `w` and `n` must agree. len(w): %d, n: %d", len(w), n)
+	}
+	af.w = w
+	return nil
+}
+
+//Predict calculates the new estimated value `y` from input slice `x`.
+func (af *genericBase[T]) Predict(x []T) (y T) {
+	return dot(af.w, x)
+}
+
+//SetWeights sets the filter weights. The length of `w` must be the filter length `n`.
+//If `w` is nil, the weights are set to zeros.
+//The slice `w` is copied.
+func (af *genericBase[T]) SetWeights(w []T) error {
+	if w != nil {
+		w = append([]T{}, w...)
+	}
+	return af.initWeights(w, af.n)
+}
+
+//Reset sets the filter weights to zeros.
+func (af *genericBase[T]) Reset() {
+	af.w = make([]T, af.n)
+}
+
+//SetStepSize set a update step size mu.
+func (af *genericBase[T]) SetStepSize(mu T) error {
+	if float64(mu) < af.muMin || af.muMax < float64(mu) {
+		return fmt.Errorf("parameter mu is not in range <%v, %v>", af.muMin, af.muMax)
+	}
+	af.mu = mu
+	return nil
+}
+
+//GetParams returns the parameters at the time this func is called.
+//parameters contains `n`: filter length, `mu`: filter update step size and `w`: filter weights.
+func (af *genericBase[T]) GetParams() (int, T, []T) {
+	return af.n, af.mu, af.w
+}
+
+//GetKindName returns the name of ADF.
+func (af *genericBase[T]) GetKindName() string {
+	return af.kind
+}
+
+//clone returns a deep copy of the base.
+func (af *genericBase[T]) clone() genericBase[T] {
+	altaf := *af
+	altaf.w = append([]T{}, af.w...)
+	return altaf
+}
+
+//GenericLMS is LMS filter working in float32 or float64.
+//The results agree with FiltLMS within the precision of `T`.
+//Use NewGenericLMS to make instance.
+type GenericLMS[T Float] struct {
+	genericBase[T]
+}
+
+//NewGenericLMS is constructor of generic LMS filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+func NewGenericLMS[T Float](n int, mu T, w []T) (*GenericLMS[T], error) {
+	p := new(GenericLMS[T])
+	if err := p.init(kindGenericLMS, n, mu, w); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *GenericLMS[T]) Adapt(d T, x []T) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *GenericLMS[T]) Step(d T, x []T) (y, e T) {
+	y = dot(af.w, x)
+	e = d - y
+	axpy(af.mu*e, x, af.w)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *GenericLMS[T]) Run(d []T, x [][]T, opts ...RunOption) (y []T, e []T, wHist [][]T, err error) {
+	return runGeneric(&af.genericBase, d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *GenericLMS[T]) Clone() *GenericLMS[T] {
+	return &GenericLMS[T]{genericBase: af.clone()}
+}
+
+//GenericNLMS is NLMS filter working in float32 or float64.
+//The results agree with FiltNLMS within the precision of `T`.
+//Use NewGenericNLMS to make instance.
+type GenericNLMS[T Float] struct {
+	genericBase[T]
+	eps T
+}
+
+//NewGenericNLMS is constructor of generic NLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps` and filter weight `w`.
+func NewGenericNLMS[T Float](n int, mu T, eps T, w []T) (*GenericNLMS[T], error) {
+	p := new(GenericNLMS[T])
+	if err := p.init(kindGenericNLMS, n, mu, w); err != nil {
+		return nil, err
+	}
+	if eps < 0 || 1 < eps {
+		return nil, fmt.Errorf("parameter eps is not in range <%v, %v>", 0, 1)
+	}
+	p.eps = eps
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *GenericNLMS[T]) Adapt(d T, x []T) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *GenericNLMS[T]) Step(d T, x []T) (y, e T) {
+	y = dot(af.w, x)
+	e = d - y
+	axpy(af.mu/(af.eps+dot(x, x))*e, x, af.w)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *GenericNLMS[T]) Run(d []T, x [][]T, opts ...RunOption) (y []T, e []T, wHist [][]T, err error) {
+	return runGeneric(&af.genericBase, d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *GenericNLMS[T]) Clone() *GenericNLMS[T] {
+	return &GenericNLMS[T]{genericBase: af.clone(), eps: af.eps}
+}
+
+//runGeneric is the adaptation loop shared by the generic filters.
+//`step` is the Step method of the filter.
+func runGeneric[T Float](af *genericBase[T], d []T, x [][]T, opts []RunOption, step func(d T, x []T) (y, e T)) (y []T, e []T, wHist [][]T, err error) {
+	//measure the data and check if the dimension agree
+	N := len(x)
+	if len(d) != N {
+		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+	}
+	if N > 0 && len(x[0]) != af.n {
+		return nil, nil, nil, fmt.Errorf("the length of rows of x and `n` must agree. len(x[0]): %d, n: %d", len(x[0]), af.n)
+	}
+	hist := newHistoryOf[T](N, af.n, opts)
+
+	y = make([]T, N)
+	e = make([]T, N)
+	//adaptation loop
+	for i := 0; i < N; i++ {
+		if err := hist.Record(i, af.w); err != nil {
+			return nil, nil, nil, err
+		}
+		y[i], e[i] = step(d[i], x[i])
+		hist.recordStepSize(i, float64(af.mu))
+	}
+	return y, e, hist.Result(), nil
+}
+
+//dot returns the dot product of `x` and `y`.
+func dot[T Float](x, y []T) (s T) {
+	for i, v := range x {
+		s += v * y[i]
+	}
+	return s
+}
+
+//axpy adds `alpha*x` to `y` in place.
+func axpy[T Float](alpha T, x, y []T) {
+	for i, v := range x {
+		y[i] += alpha * v
+	}
+}
@@ -0,0 +1,140 @@
+package adf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//toFloat32s converts the matrix `x` to float32.
+func toFloat32s(x [][]float64) [][]float32 {
+	x32 := make([][]float32, len(x))
+	for i := range x {
+		x32[i] = make([]float32, len(x[i]))
+		for j, v := range x[i] {
+			x32[i][j] = float32(v)
+		}
+	}
+	return x32
+}
+
+//toFloat64s converts the slice `x` to float64.
+func toFloat64s[T Float](x []T) []float64 {
+	x64 := make([]float64, len(x))
+	for i, v := range x {
+		x64[i] = float64(v)
+	}
+	return x64
+}
+
+func TestGenericLMS_Run(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.05)
+	d32 := toFloat32s([][]float64{d})[0]
+	x32 := toFloat32s(x)
+
+	wantY, wantE, wantW, err := Must(NewFiltLMS(4, 0.05, nil)).Run(d, x)
+	check(err)
+
+	lms64, err := NewGenericLMS[float64](4, 0.05, nil)
+	check(err)
+	y, e, wHist, err := lms64.Run(d, x)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !floats.EqualApprox(y, wantY, 1e-12) || !floats.EqualApprox(e, wantE, 1e-12) {
+		t.Errorf("GenericLMS[float64].Run() differs from FiltLMS.Run()")
+	}
+	for i := range wantW {
+		if !floats.EqualApprox(wHist[i], wantW[i], 1e-12) {
+			t.Fatalf("GenericLMS[float64].Run() wHist[%d] = %v, want %v", i, wHist[i], wantW[i])
+		}
+	}
+
+	lms32, err := NewGenericLMS[float32](4, 0.05, nil)
+	check(err)
+	y32, e32, wHist32, err := lms32.Run(d32, x32)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !floats.EqualApprox(toFloat64s(y32), wantY, 1e-4) || !floats.EqualApprox(toFloat64s(e32), wantE, 1e-4) {
+		t.Errorf("GenericLMS[float32].Run() differs from FiltLMS.Run()")
+	}
+	for i := range wantW {
+		if !floats.EqualApprox(toFloat64s(wHist32[i]), wantW[i], 1e-4) {
+			t.Fatalf("GenericLMS[float32].Run() wHist[%d] = %v, want %v", i, wHist32[i], wantW[i])
+		}
+	}
+}
+
+func TestGenericNLMS_Run(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.05)
+	d32 := toFloat32s([][]float64{d})[0]
+	x32 := toFloat32s(x)
+
+	wantY, wantE, _, err := Must(NewFiltNLMS(4, 0.5, 1e-3, nil)).Run(d, x)
+	check(err)
+
+	nlms64, err := NewGenericNLMS[float64](4, 0.5, 1e-3, nil)
+	check(err)
+	y, e, _, err := nlms64.Run(d, x)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !floats.EqualApprox(y, wantY, 1e-12) || !floats.EqualApprox(e, wantE, 1e-12) {
+		t.Errorf("GenericNLMS[float64].Run() differs from FiltNLMS.Run()")
+	}
+
+	nlms32, err := NewGenericNLMS[float32](4, 0.5, 1e-3, nil)
+	check(err)
+	y32, e32, _, err := nlms32.Run(d32, x32)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !floats.EqualApprox(toFloat64s(y32), wantY, 1e-4) || !floats.EqualApprox(toFloat64s(e32), wantE, 1e-4) {
+		t.Errorf("GenericNLMS[float32].Run() differs from FiltNLMS.Run()")
+	}
+	//Adapt agrees with Run
+	alt, err := NewGenericNLMS[float32](4, 0.5, 1e-3, nil)
+	check(err)
+	for i := range x32 {
+		alt.Adapt(d32[i], x32[i])
+	}
+	_, _, w := nlms32.GetParams()
+	_, _, wAlt := alt.GetParams()
+	if !floats.Equal(toFloat64s(w), toFloat64s(wAlt)) {
+		t.Errorf("GenericNLMS[float32].Adapt() w = %v, want %v", wAlt, w)
+	}
+}
+
+func TestNewGenericNLMS_invalidParams(t *testing.T) {
+	if _, err := NewGenericNLMS[float32](4, 3, 1e-3, nil); err == nil {
+		t.Errorf("NewGenericNLMS() with mu = 3: error = nil, want error")
+	}
+	if _, err := NewGenericNLMS[float32](4, 0.5, 2, nil); err == nil {
+		t.Errorf("NewGenericNLMS() with eps = 2: error = nil, want error")
+	}
+	if _, err := NewGenericNLMS[float32](4, 0.5, 1e-3, make([]float32, 3)); err == nil {
+		t.Errorf("NewGenericNLMS() with len(w) = 3: error = nil, want error")
+	}
+}
+
+func ExampleGenericLMS() {
+	rand.Seed(1)
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(512, h, 0.01)
+
+	//the filter works in float32
+	af, err := NewGenericLMS[float32](len(h), 0.1, nil)
+	check(err)
+	_, _, _, err = af.Run(toFloat32s([][]float64{d})[0], toFloat32s(x), WithoutHistory())
+	check(err)
+	_, _, w := af.GetParams()
+	fmt.Printf("w: %.2f\n", w)
+	//output:
+	//w: [0.40 -0.20 0.10 0.05]
+}
@@ -0,0 +1,119 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindGNGD is the kind name of FiltGNGD.
+const kindGNGD = "GNGD filter"
+
+//gngdEpsMin is the floor of the regularization term of FiltGNGD.
+//The gradient descent can drive `eps` to zero or below, which makes the normalization singular.
+const gngdEpsMin = 1e-6
+
+//FiltGNGD is base struct for GNGD filter (Generalized Normalized Gradient Descent filter).
+//It is NLMS filter whose regularization term `eps` is adapted by gradient descent:
+//eps <- eps - rho*mu*e(k)*e(k-1)*x(k)·x(k-1) / (x(k-1)·x(k-1) + eps)^2.
+//`eps` is clamped to 1e-6 or larger.
+//Use NewFiltGNGD to make instance.
+type FiltGNGD struct {
+	filtBase
+	eps   float64
+	eps0  float64
+	rho   float64
+	ePrev float64
+	xPrev []float64
+}
+
+//NewFiltGNGD is constructor of GNGD filter.
+//This func initialize filter length `n`, update step size `mu`, initial regularization term `eps`,
+//the learning rate of `eps` `rho` and filter weight `w`.
+//Typical values are `mu` = 1, `eps` = 1 and `rho` = 0.1.
+func NewFiltGNGD(n int, mu float64, eps float64, rho float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltGNGD)
+	p.kind = kindGNGD
+	p.n = n
+	p.muMin = 0
+	p.muMax = 2
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, gngdEpsMin, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	p.eps0 = p.eps
+	p.rho, err = p.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.xPrev = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update `eps` and filter weights according to error `e`.
+func (af *FiltGNGD) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update `eps` and filter weights according to error `e`.
+func (af *FiltGNGD) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	den := floats.Dot(af.xPrev, af.xPrev) + af.eps
+	af.eps -= af.rho * af.mu * e * af.ePrev * floats.Dot(x, af.xPrev) / (den * den)
+	if af.eps < gngdEpsMin {
+		af.eps = gngdEpsMin
+	}
+	nu := af.mu / (af.eps + floats.Dot(x, x))
+	floats.AddScaled(w, nu*e, x)
+	af.ePrev = e
+	copy(af.xPrev, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating `eps` and filter weights according to error `e`.
+//Use WithEpsHistory to get the trajectory of `eps`.
+func (af *FiltGNGD) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, &runHooks{
+		init: func(N int, hist *historyRecorder[float64]) {
+			hist.initEps(N)
+		},
+		after: func(i int, hist *historyRecorder[float64]) error {
+			hist.recordEps(i, af.eps)
+			return nil
+		},
+	})
+}
+
+//GetEps returns the current regularization term `eps`.
+func (af *FiltGNGD) GetEps() float64 {
+	return af.eps
+}
+
+//Reset sets the filter weights and the previous error and input to zeros, and `eps` to the initial value.
+func (af *FiltGNGD) Reset() {
+	af.filtBase.Reset()
+	af.eps = af.eps0
+	af.ePrev = 0
+	af.xPrev = make([]float64, af.n)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltGNGD) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.xPrev = append([]float64{}, af.xPrev...)
+	return &altaf
+}
@@ -0,0 +1,128 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//padasipGNGD is a direct transcription of the learning rule of GNGD in padasip.
+func padasipGNGD(d []float64, x [][]float64, mu, eps, ro float64) (w []float64, epsHist []float64) {
+	n := len(x[0])
+	w = make([]float64, n)
+	lastE := 0.0
+	lastX := make([]float64, n)
+	for k := range d {
+		e := d[k] - floats.Dot(w, x[k])
+		eps = eps - ro*mu*e*lastE*floats.Dot(x[k], lastX)/
+			((floats.Dot(lastX, lastX)+eps)*(floats.Dot(lastX, lastX)+eps))
+		nu := mu / (eps + floats.Dot(x[k], x[k]))
+		for i := range w {
+			w[i] += nu * e * x[k][i]
+		}
+		lastE, lastX = e, x[k]
+		epsHist = append(epsHist, eps)
+	}
+	return w, epsHist
+}
+
+func TestFiltGNGD_Run(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(1024, h, 0.05)
+	//the input power swings over a wide range
+	for i := range x {
+		g := 1.0
+		if (i/128)%2 == 1 {
+			g = 0.05
+		}
+		floats.Scale(g, x[i])
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*0.05*g
+	}
+	af := Must(NewFiltGNGD(len(h), 1, 1, 0.1, nil))
+	var epsHist []float64
+	_, _, _, err := af.Run(d, x, WithEpsHistory(&epsHist))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	wantW, wantEps := padasipGNGD(d, x, 1, 1, 0.1)
+	if _, _, w := af.GetParams(); !floats.EqualApprox(w, wantW, 1e-12) {
+		t.Errorf("Run() w = %v, want %v", w, wantW)
+	}
+	if !floats.EqualApprox(epsHist, wantEps, 1e-12) {
+		t.Errorf("Run() eps history differs from padasip")
+	}
+	if got := af.(*FiltGNGD).GetEps(); got != epsHist[len(epsHist)-1] {
+		t.Errorf("GetEps() = %v, want %v", got, epsHist[len(epsHist)-1])
+	}
+	if floats.Min(epsHist) == floats.Max(epsHist) {
+		t.Errorf("Run() eps is not adapted: %v", epsHist[0])
+	}
+	if _, _, w := af.GetParams(); !floats.EqualApprox(w, h, 0.05) {
+		t.Errorf("Run() w = %v, want %v", w, h)
+	}
+	af.Reset()
+	if got := af.(*FiltGNGD).GetEps(); got != 1 {
+		t.Errorf("Reset() eps = %v, want 1", got)
+	}
+}
+
+func TestFiltGNGD_epsFloor(t *testing.T) {
+	//the errors of the ramp keep their sign and the gradient drives eps below zero
+	N := 64
+	x := make([][]float64, N)
+	d := make([]float64, N)
+	for i := range x {
+		x[i] = []float64{1, 1, 1, 1}
+		d[i] = 10 * float64(i)
+	}
+	af := Must(NewFiltGNGD(4, 1, 0.01, 1, nil))
+	var epsHist []float64
+	_, e, _, err := af.Run(d, x, WithEpsHistory(&epsHist))
+	check(err)
+	if m := floats.Min(epsHist); m != gngdEpsMin {
+		t.Errorf("Run() min eps = %v, want %v", m, gngdEpsMin)
+	}
+	for i, v := range e {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Fatalf("Run() e[%d] = %v, want finite", i, v)
+		}
+	}
+}
+
+func TestNewFiltGNGD_invalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		mu, eps, rho float64
+	}{
+		{name: "mu", mu: 2.5, eps: 1, rho: 0.1},
+		{name: "eps", mu: 1, eps: -1, rho: 0.1},
+		{name: "rho", mu: 1, eps: 1, rho: 2},
+	}
+	for _, tt := range tests {
+		if _, err := NewFiltGNGD(4, tt.mu, tt.eps, tt.rho, nil); err == nil {
+			t.Errorf("NewFiltGNGD() with invalid %s: error = nil, want error", tt.name)
+		}
+	}
+}
+
+func ExampleFiltGNGD_Run() {
+	rand.Seed(1)
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(512, h, 0.01)
+
+	af := Must(NewFiltGNGD(len(h), 1, 1, 0.1, nil))
+	var eps []float64
+	_, _, _, err := af.Run(d, x, WithoutHistory(), WithEpsHistory(&eps))
+	check(err)
+	_, _, w := af.GetParams()
+	fmt.Printf("eps: %.3f -> %.3f\n", 1.0, eps[len(eps)-1])
+	fmt.Printf("w: %.2f\n", w)
+	//output:
+	//eps: 1.000 -> 1.003
+	//w: [0.39 -0.21 0.10 0.05]
+}
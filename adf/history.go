@@ -0,0 +1,261 @@
+package adf
+
+import (
+	"io"
+
+	"github.com/tetsuzawa/go-adflib/internal/adfutil"
+	"gonum.org/v1/gonum/mat"
+)
+
+//RunOption configures the weight history recorded by Run.
+//By default Run returns the weights before every sample, that is `N` rows of `n` weights.
+type RunOption func(c *runConfig)
+
+//runConfig is the configuration built from RunOption.
+type runConfig struct {
+	adfutil.HistoryConfig
+	muHist  *[]float64
+	epsHist *[]float64
+	updates *UpdateReport
+	lattice *LatticeReport
+	covHist *[][]float64
+}
+
+//WithoutHistory disables the weight history. Run returns nil as `wHist`.
+func WithoutHistory() RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+	}
+}
+
+//WithHistoryEvery records only every `k`-th weight snapshot, that is the weights before samples 0, k, 2k, ....
+//The returned `wHist` has ceil(N/k) rows. It also applies to WithHistoryFunc and WithHistoryWriter.
+//If `k` is smaller than 1, every snapshot is recorded.
+func WithHistoryEvery(k int) RunOption {
+	return func(c *runConfig) {
+		if k < 1 {
+			k = 1
+		}
+		c.Every = k
+	}
+}
+
+//WithHistoryFunc streams the weight snapshots to `fn` instead of keeping them in memory.
+//`i` is the index of the sample and `w` is the weights before the sample.
+//`w` is only valid during the call. If `fn` returns an error, Run stops and returns it.
+//The filters working in float32 pass the weights converted to float64.
+func WithHistoryFunc(fn func(i int, w []float64) error) RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+		c.Fn = fn
+	}
+}
+
+//WithHistoryWriter streams the weight snapshots to `w` instead of keeping them in memory.
+//Each snapshot is written as `n` little-endian float64 values, also for the filters working in float32.
+//If a write fails, Run stops and returns the error.
+func WithHistoryWriter(w io.Writer) RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+		c.Writer = w
+	}
+}
+
+//WithStepSizeHistory stores the step size after every sample to `mu`,
+//that is the value GetParams returns after the sample. The slice has `N` values regardless of WithHistoryEvery.
+//It is useful for the filters which adapt the step size, such as FiltVSSLMS.
+//Every filter of this package fills it. For the filters with a fixed step size the values are constant,
+//and for FiltKalman they are the variance of the process noise `q`.
+func WithStepSizeHistory(mu *[]float64) RunOption {
+	return func(c *runConfig) {
+		c.muHist = mu
+	}
+}
+
+//WithEpsHistory stores the regularization term `eps` after every sample to `eps`.
+//The slice has `N` values regardless of WithHistoryEvery.
+//It is filled by the filters which adapt `eps`, such as FiltGNGD. Other filters leave `eps` untouched.
+func WithEpsHistory(eps *[]float64) RunOption {
+	return func(c *runConfig) {
+		c.epsHist = eps
+	}
+}
+
+//UpdateReport is the report of the data-selective updates of Run.
+type UpdateReport struct {
+	//Updated reports whether the weights were updated at each sample.
+	Updated []bool
+	//Count is the number of the samples which triggered an update.
+	Count int
+	//Ratio is Count divided by the number of samples.
+	Ratio float64
+}
+
+//WithUpdateReport reports which samples triggered an update of the weights to `r`.
+//It is filled by the filters which update only for some samples, such as FiltSMNLMS.
+//Other filters leave `r` untouched.
+func WithUpdateReport(r *UpdateReport) RunOption {
+	return func(c *runConfig) {
+		c.updates = r
+	}
+}
+
+//LatticeReport is the report of the order-recursive quantities of the lattice filters.
+type LatticeReport struct {
+	//OrderErrors is the a priori estimation errors of the orders 0 to `n` at each sample.
+	//OrderErrors[i][0] is the desired value and OrderErrors[i][n] is the error `e` of Run.
+	OrderErrors [][]float64
+	//Forward and Backward are the forward and backward reflection coefficients of the `n-1` stages after each sample.
+	Forward  [][]float64
+	Backward [][]float64
+}
+
+//WithLatticeReport reports the estimation errors of every order and the reflection coefficients to `r`.
+//It is filled by the lattice filters, such as FiltLSL, and ignored by other filters.
+func WithLatticeReport(r *LatticeReport) RunOption {
+	return func(c *runConfig) {
+		c.lattice = r
+	}
+}
+
+//WithCovarianceHistory stores the diagonal of the covariance matrix of the weights to `p`,
+//that is the variance of each weight. The rows correspond to the rows of the weight history:
+//the variances before the recorded samples, also subsampled by WithHistoryEvery.
+//The square root of the variances gives the confidence bands of the weights.
+//It is filled by the filters which estimate the covariance, such as FiltKalman, and ignored by other filters.
+func WithCovarianceHistory(p *[][]float64) RunOption {
+	return func(c *runConfig) {
+		c.covHist = p
+	}
+}
+
+//newRunConfig applies `opts` to the default configuration.
+func newRunConfig(opts []RunOption) *runConfig {
+	c := &runConfig{HistoryConfig: adfutil.NewHistoryConfig()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//historyRecorder records the weight history of Run and the extra outputs according to runConfig.
+type historyRecorder[T any] struct {
+	*runConfig
+	*adfutil.History[T]
+}
+
+//newHistory makes historyRecorder for `N` samples and `n` weights of float64.
+func newHistory(N, n int, opts []RunOption) *historyRecorder[float64] {
+	return newHistoryOf[float64](N, n, opts)
+}
+
+//newHistoryOf makes historyRecorder for `N` samples and `n` weights of type `T`.
+func newHistoryOf[T Float](N, n int, opts []RunOption) *historyRecorder[T] {
+	c := newRunConfig(opts)
+	return newRecorder(c, N, adfutil.NewFloatHistory[T](c.HistoryConfig, N, n))
+}
+
+//newComplexHistory makes historyRecorder for `N` samples and `n` complex weights.
+//WithHistoryFunc and WithHistoryWriter receive the real and imaginary parts interleaved,
+//that is `2n` float64 values.
+func newComplexHistory(N, n int, opts []RunOption) *historyRecorder[complex128] {
+	c := newRunConfig(opts)
+	return newRecorder(c, N, adfutil.NewComplexHistory(c.HistoryConfig, N, n))
+}
+
+//newRecorder makes historyRecorder for `N` samples from the configuration `c` and the weight history `h`.
+func newRecorder[T any](c *runConfig, N int, h *adfutil.History[T]) *historyRecorder[T] {
+	if c.muHist != nil {
+		*c.muHist = make([]float64, N)
+	}
+	return &historyRecorder[T]{runConfig: c, History: h}
+}
+
+//recordStepSize records the step size `mu` after the sample `i`.
+func (h *historyRecorder[T]) recordStepSize(i int, mu float64) {
+	if h.muHist != nil {
+		(*h.muHist)[i] = mu
+	}
+}
+
+//initEps makes the history of `eps` for `N` samples.
+//It is called by the filters which record `eps`.
+func (h *historyRecorder[T]) initEps(N int) {
+	if h.epsHist != nil {
+		*h.epsHist = make([]float64, N)
+	}
+}
+
+//recordEps records the regularization term `eps` after the sample `i`.
+func (h *historyRecorder[T]) recordEps(i int, eps float64) {
+	if h.epsHist != nil {
+		(*h.epsHist)[i] = eps
+	}
+}
+
+//initUpdates makes UpdateReport for `N` samples.
+//It is called by the filters which record the updates.
+func (h *historyRecorder[T]) initUpdates(N int) {
+	if h.updates != nil {
+		*h.updates = UpdateReport{Updated: make([]bool, N)}
+	}
+}
+
+//recordUpdate records whether the weights were updated at the sample `i`.
+func (h *historyRecorder[T]) recordUpdate(i int, updated bool) {
+	if h.updates == nil {
+		return
+	}
+	h.updates.Updated[i] = updated
+	if updated {
+		h.updates.Count++
+	}
+	h.updates.Ratio = float64(h.updates.Count) / float64(i+1)
+}
+
+//initLattice makes LatticeReport for `N` samples and the filter length `n`.
+func (h *historyRecorder[T]) initLattice(N, n int) {
+	if h.lattice == nil {
+		return
+	}
+	newRows := func(m int) [][]float64 {
+		rows := make([][]float64, N)
+		for i := range rows {
+			rows[i] = make([]float64, m)
+		}
+		return rows
+	}
+	*h.lattice = LatticeReport{OrderErrors: newRows(n + 1), Forward: newRows(n - 1), Backward: newRows(n - 1)}
+}
+
+//recordLattice records the errors of every order `e` and the reflection coefficients `kf` and `kb` of the sample `i`.
+func (h *historyRecorder[T]) recordLattice(i int, e, kf, kb []float64) {
+	if h.lattice == nil {
+		return
+	}
+	copy(h.lattice.OrderErrors[i], e)
+	copy(h.lattice.Forward[i], kf)
+	copy(h.lattice.Backward[i], kb)
+}
+
+//initCovariance makes the covariance history for `N` samples and the filter length `n`.
+func (h *historyRecorder[T]) initCovariance(N, n int) {
+	if h.covHist == nil {
+		return
+	}
+	*h.covHist = make([][]float64, (N+h.Every-1)/h.Every)
+	for i := range *h.covHist {
+		(*h.covHist)[i] = make([]float64, n)
+	}
+}
+
+//recordCovariance records the diagonal of the covariance matrix `p` before the sample `i`.
+func (h *historyRecorder[T]) recordCovariance(i int, p *mat.Dense) {
+	if h.covHist == nil || i%h.Every != 0 {
+		return
+	}
+	row := (*h.covHist)[i/h.Every]
+	for j := range row {
+		row[j] = p.At(j, j)
+	}
+}
@@ -0,0 +1,159 @@
+package adf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestFiltLMS_Run_history(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 64
+	L := 4
+	//input value
+	var x = make([][]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = x[i][L-1]
+	}
+	_, _, full, err := Must(NewFiltLMS(L, 0.1, nil)).Run(d, x)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(full) != n {
+		t.Fatalf("Run() len(wHist) = %d, want %d", len(full), n)
+	}
+
+	var every3 [][]float64
+	for i := 0; i < n; i += 3 {
+		every3 = append(every3, full[i])
+	}
+	var streamed [][]float64
+	var indices []int
+	var buf bytes.Buffer
+	var written = make([]float64, n*L)
+
+	tests := []struct {
+		name string
+		opts []RunOption
+		want [][]float64
+	}{
+		{name: "without history", opts: []RunOption{WithoutHistory()}, want: nil},
+		{name: "every 3", opts: []RunOption{WithHistoryEvery(3)}, want: every3},
+		{name: "every 0", opts: []RunOption{WithHistoryEvery(0)}, want: full},
+		{
+			name: "func",
+			opts: []RunOption{WithHistoryFunc(func(i int, w []float64) error {
+				indices = append(indices, i)
+				streamed = append(streamed, append([]float64{}, w...))
+				return nil
+			})},
+			want: nil,
+		},
+		{name: "writer", opts: []RunOption{WithHistoryWriter(&buf)}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y, e, wHist, err := Must(NewFiltLMS(L, 0.1, nil)).Run(d, x, tt.opts...)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(y) != n || len(e) != n {
+				t.Errorf("Run() len(y) = %d, len(e) = %d, want %d", len(y), len(e), n)
+			}
+			if !reflect.DeepEqual(wHist, tt.want) {
+				t.Errorf("Run() wHist = %v, want %v", wHist, tt.want)
+			}
+		})
+	}
+	if !reflect.DeepEqual(streamed, full) || len(indices) != n || indices[n-1] != n-1 {
+		t.Errorf("WithHistoryFunc() streamed = %v, want %v", streamed, full)
+	}
+	if err := binary.Read(&buf, binary.LittleEndian, written); err != nil {
+		t.Fatalf("binary.Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(written, misc.Floor(full)) {
+		t.Errorf("WithHistoryWriter() written = %v, want %v", written, misc.Floor(full))
+	}
+}
+
+func TestFiltLMS_Run_historyError(t *testing.T) {
+	wantErr := errors.New("disk full")
+	af := Must(NewFiltLMS(2, 0.1, nil))
+	_, _, _, err := af.Run([]float64{1, 2}, [][]float64{{1, 0}, {0, 1}}, WithHistoryFunc(func(i int, w []float64) error {
+		return wantErr
+	}))
+	if err == nil {
+		t.Errorf("Run() error = nil, want error")
+	}
+}
+
+func TestWithStepSizeHistory(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(32, []float64{0.4, -0.2}, 0.1)
+	var mus []float64
+	_, _, wHist, err := Must(NewFiltNLMS(2, 0.5, 1e-5, nil)).Run(d, x, WithHistoryEvery(4), WithStepSizeHistory(&mus))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(wHist) != 8 {
+		t.Errorf("Run() len(wHist) = %d, want 8", len(wHist))
+	}
+	//the step size of NLMS is fixed
+	want := make([]float64, len(d))
+	for i := range want {
+		want[i] = 0.5
+	}
+	if !reflect.DeepEqual(mus, want) {
+		t.Errorf("Run() mu history = %v, want %v", mus, want)
+	}
+	//the base filter records the step size as well
+	mus = nil
+	_, _, _, err = Must(newFiltBase(2, 0.5, nil)).Run(d, x, WithStepSizeHistory(&mus))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(mus, want) {
+		t.Errorf("Run() of base filter mu history = %v, want %v", mus, want)
+	}
+}
+
+func TestWithEpsHistory_untouched(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(32, []float64{0.4, -0.2}, 0.1)
+	//LMS does not adapt eps and leaves the slice of the caller as it is
+	eps := []float64{1, 2, 3}
+	_, _, _, err := Must(NewFiltLMS(2, 0.1, nil)).Run(d, x, WithEpsHistory(&eps))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []float64{1, 2, 3}; !reflect.DeepEqual(eps, want) {
+		t.Errorf("Run() eps history = %v, want %v", eps, want)
+	}
+}
+
+func TestWithUpdateReport_untouched(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(32, []float64{0.4, -0.2}, 0.1)
+	//NLMS updates for every sample and leaves the report of the caller as it is
+	r := UpdateReport{Count: 7}
+	_, _, _, err := Must(NewFiltNLMS(2, 0.5, 1e-5, nil)).Run(d, x, WithUpdateReport(&r))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := (UpdateReport{Count: 7}); !reflect.DeepEqual(r, want) {
+		t.Errorf("Run() update report = %+v, want %+v", r, want)
+	}
+}
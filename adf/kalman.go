@@ -0,0 +1,158 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindKalman is the kind name of FiltKalman.
+const kindKalman = "Kalman filter"
+
+//FiltKalman is base struct for Kalman filter based adaptive filter.
+//The weights are the state vector of the random-walk model
+//	w(k) = w(k-1) + u(k),  d(k) = w(k)^T x(k) + v(k),
+//where the process noise u has the covariance q I and the measurement noise v has the variance `r`.
+//The filter estimates the weights and their covariance matrix `pMat`.
+//The ratio q/r sets the trade-off between the tracking and the steady-state error,
+//and the diagonal of `pMat` is the uncertainty of each weight.
+//With q = 0, it is FiltRLS with mu = 1 and eps = r.
+//
+//The process noise `q` plays the role of the step size: GetParams returns it as `mu` and SetStepSize sets it.
+//Unlike the step sizes of the other filters, it is not capped at 1.
+//Use NewFiltKalman to make instance.
+type FiltKalman struct {
+	filtBase
+	q    float64
+	r    float64
+	pMat *mat.Dense
+	//k is the workspace for P x.
+	k []float64
+}
+
+//NewFiltKalman is constructor of Kalman filter.
+//This func initialize filter length `n`, the variance of the process noise `q`,
+//the variance of the measurement noise `r` and filter weight `w`.
+//The covariance matrix of the weights starts from the identity matrix.
+func NewFiltKalman(n int, q, r float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltKalman)
+	p.kind = kindKalman
+	p.n = n
+	p.q, err = p.checkFloatParam(q, 0, math.MaxFloat64, "q")
+	if err != nil {
+		return nil, err
+	}
+	p.r, err = p.checkFloatParam(r, math.SmallestNonzeroFloat64, math.MaxFloat64, "r")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.initPMat()
+	p.k = make([]float64, n)
+	return p, nil
+}
+
+//Variances returns the diagonal of the covariance matrix of the weights, that is the variance of each weight.
+func (af *FiltKalman) Variances() []float64 {
+	v := make([]float64, af.n)
+	for i := range v {
+		v[i] = af.pMat.At(i, i)
+	}
+	return v
+}
+
+//SetStepSize sets the variance of the process noise `q`.
+func (af *FiltKalman) SetStepSize(q float64) error {
+	q, err := af.checkFloatParam(q, 0, math.MaxFloat64, "q")
+	if err != nil {
+		return err
+	}
+	af.q = q
+	return nil
+}
+
+//GetParams returns the parameters at the time this func is called.
+//The variance of the process noise `q` is returned as `mu`.
+func (af *FiltKalman) GetParams() (int, float64, []float64) {
+	return af.n, af.q, af.w.RawRowView(0)
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltKalman) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights and their covariance matrix.
+//
+//With P = P + q I and k = P x, the weights are updated by
+//	w = w + e k / (r + x^T k)
+//and the covariance matrix by
+//	P = P - k k^T / (r + x^T k).
+func (af *FiltKalman) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	for i := 0; i < af.n; i++ {
+		af.pMat.Set(i, i, af.pMat.At(i, i)+af.q)
+	}
+	k := af.k
+	for i := 0; i < af.n; i++ {
+		k[i] = floats.Dot(af.pMat.RawRowView(i), x)
+	}
+	den := af.r + floats.Dot(x, k)
+	for i := 0; i < af.n; i++ {
+		floats.AddScaled(af.pMat.RawRowView(i), -k[i]/den, k)
+	}
+	floats.AddScaled(w, e/den, k)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights and their covariance matrix.
+//The diagonal of the covariance matrix is recorded by WithCovarianceHistory.
+func (af *FiltKalman) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, &runHooks{
+		init: func(N int, hist *historyRecorder[float64]) {
+			hist.initCovariance(N, af.n)
+		},
+		before: func(i int, hist *historyRecorder[float64]) {
+			hist.recordCovariance(i, af.pMat)
+		},
+		after: func(i int, hist *historyRecorder[float64]) error {
+			//the step size of the filter is `q`
+			hist.recordStepSize(i, af.q)
+			return nil
+		},
+	})
+}
+
+//Reset sets the filter weights to zeros and the covariance matrix to the identity matrix.
+func (af *FiltKalman) Reset() {
+	af.filtBase.Reset()
+	af.initPMat()
+}
+
+//initPMat initializes the covariance matrix `pMat` to the identity matrix.
+func (af *FiltKalman) initPMat() {
+	var Ps = make([]float64, af.n*af.n)
+	for i := 0; i < af.n; i++ {
+		Ps[i*(af.n+1)] = 1
+	}
+	af.pMat = mat.NewDense(af.n, af.n, Ps)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltKalman) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.pMat = mat.DenseCopyOf(af.pMat)
+	altaf.k = make([]float64, len(af.k))
+	return &altaf
+}
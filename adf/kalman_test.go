@@ -0,0 +1,173 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+//newRandomWalkData makes the white input and the desired values of the system whose weights follow
+//the random walk with the variance `q` from `h`, with the measurement noise of the variance `r`.
+//It also returns the weights of the system at each sample.
+func newRandomWalkData(n int, h []float64, q, r float64) (d []float64, x [][]float64, hHist [][]float64) {
+	L := len(h)
+	x = make([][]float64, n)
+	d = make([]float64, n)
+	hHist = make([][]float64, n)
+	hk := append([]float64{}, h...)
+	xRow := make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		for j := range hk {
+			hk[j] += math.Sqrt(q) * rand.NormFloat64()
+		}
+		hHist[i] = append([]float64{}, hk...)
+		d[i] = floats.Dot(hk, x[i]) + math.Sqrt(r)*rand.NormFloat64()
+	}
+	return d, x, hHist
+}
+
+func TestFiltKalman_agreesWithRLS(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	//without the process noise, the Kalman filter is RLS without forgetting
+	af := Must(NewFiltKalman(4, 0, 0.1, nil))
+	want := Must(NewFiltRLS(4, 1, 0.1, nil))
+	_, e, wHist, err := af.Run(d, x)
+	check(err)
+	_, wantE, wantWHist, err := want.Run(d, x)
+	check(err)
+	for i := range d {
+		if !floats.EqualWithinAbs(e[i], wantE[i], 1e-12) || !floats.EqualApprox(wHist[i], wantWHist[i], 1e-9) {
+			t.Fatalf("Run() e[%d], wHist[%d] = %v, %v, want %v, %v", i, i, e[i], wHist[i], wantE[i], wantWHist[i])
+		}
+	}
+}
+
+func TestFiltKalman_confidence(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	const q = 1e-5
+	const r = 1e-2
+	d, x, hHist := newRandomWalkData(5000, h, q, r)
+
+	af := Must(NewFiltKalman(len(h), q, r, nil))
+	var pHist [][]float64
+	_, _, wHist, err := af.Run(d, x, WithCovarianceHistory(&pHist))
+	check(err)
+	if len(pHist) != len(wHist) {
+		t.Fatalf("len(pHist) = %d, want %d", len(pHist), len(wHist))
+	}
+	if !reflect.DeepEqual(pHist[0], []float64{1, 1, 1, 1, 1, 1, 1, 1}) {
+		t.Errorf("pHist[0] = %v, want the initial variances 1", pHist[0])
+	}
+	//the weights before the sample i estimate the system at the sample i-1
+	inside, total := 0, 0
+	for i := 1000; i < len(d); i++ {
+		for j := range h {
+			if math.Abs(wHist[i][j]-hHist[i-1][j]) < 2*math.Sqrt(pHist[i][j]) {
+				inside++
+			}
+			total++
+		}
+	}
+	//about 95 % of the weights are in the 2-sigma band if the model is right
+	if ratio := float64(inside) / float64(total); ratio < 0.9 || 0.99 < ratio {
+		t.Errorf("ratio of the weights in the 2-sigma band = %v, want about 0.95", ratio)
+	}
+	//the uncertainty decreases from the initial variances
+	for j, v := range af.(*FiltKalman).Variances() {
+		if v <= 0 || 1e-2 < v {
+			t.Errorf("Variances()[%d] = %v, want in (0, 1e-2]", j, v)
+		}
+	}
+}
+
+func TestFiltKalman_tracking(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	const q = 1e-5
+	const r = 1e-2
+	d, x, hHist := newRandomWalkData(5000, h, q, r)
+	trackingError := func(af AdaptiveFilter) float64 {
+		var sum float64
+		_, _, _, err := af.Run(d, x, WithHistoryFunc(func(i int, w []float64) error {
+			if i >= 1000 {
+				sum += misalignment(w, hHist[i-1])
+			}
+			return nil
+		}))
+		check(err)
+		return sum / float64(len(d)-1000)
+	}
+	//the process noise lets the filter follow the moving system
+	withQ := trackingError(Must(NewFiltKalman(len(h), q, r, nil)))
+	withoutQ := trackingError(Must(NewFiltKalman(len(h), 0, r, nil)))
+	if withQ > withoutQ-5 {
+		t.Errorf("misalignment with q = %.1f dB, want 5 dB better than without q %.1f dB", withQ, withoutQ)
+	}
+}
+
+func TestNewFiltKalman_invalidParams(t *testing.T) {
+	if _, err := NewFiltKalman(4, -1, 0.1, nil); err == nil {
+		t.Errorf("NewFiltKalman() with q = -1: error = nil, want error")
+	}
+	if _, err := NewFiltKalman(4, 1e-4, 0, nil); err == nil {
+		t.Errorf("NewFiltKalman() with r = 0: error = nil, want error")
+	}
+	//q is a variance and is not capped at 1
+	af, err := NewFiltKalman(4, 2, 0.1, nil)
+	if err != nil {
+		t.Fatalf("NewFiltKalman() with q = 2: error = %v, want nil", err)
+	}
+	check(af.(StepSizeSetter).SetStepSize(10))
+	if err := af.(StepSizeSetter).SetStepSize(-1); err == nil {
+		t.Errorf("SetStepSize(-1) error = nil, want error")
+	}
+	if _, q, _ := af.GetParams(); q != 10 {
+		t.Errorf("GetParams() q = %v, want 10", q)
+	}
+}
+
+func TestFiltKalman_Run_stepSizeHistory(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(16, []float64{0.4, -0.2, 0.1, 0.05}, 0.1)
+	var mus []float64
+	_, _, _, err := Must(NewFiltKalman(4, 2, 0.1, nil)).Run(d, x, WithStepSizeHistory(&mus))
+	check(err)
+	//the step size history of the Kalman filter is `q`
+	for i, mu := range mus {
+		if mu != 2 {
+			t.Fatalf("Run() mu history[%d] = %v, want 2", i, mu)
+		}
+	}
+}
+
+func ExampleWithCovarianceHistory() {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(1000, h, 0.1)
+	af := Must(NewFiltKalman(len(h), 1e-6, 0.01, nil))
+	var pHist [][]float64
+	_, _, wHist, err := af.Run(d, x, WithHistoryEvery(500), WithCovarianceHistory(&pHist))
+	check(err)
+	for i := range wHist {
+		//the weights with the 2-sigma confidence bands
+		bands := make([]string, len(wHist[i]))
+		for j, w := range wHist[i] {
+			bands[j] = fmt.Sprintf("%.2f±%.2f", w, 2*math.Sqrt(pHist[i][j]))
+		}
+		fmt.Println(strings.Join(bands, " "))
+	}
+	//output:
+	//0.00±2.00 0.00±2.00 0.00±2.00 0.00±2.00
+	//0.39±0.02 -0.19±0.02 0.11±0.02 0.06±0.02
+}
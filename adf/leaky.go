@@ -0,0 +1,141 @@
+package adf
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the leaky filters.
+const (
+	kindLeakyLMS  = "leaky LMS filter"
+	kindLeakyNLMS = "leaky NLMS filter"
+)
+
+//FiltLeakyLMS is base struct for leaky LMS filter.
+//The weights decay by the leakage factor `gamma` in every update:
+//w = (1 - gamma) * w + mu * e * x.
+//The decay keeps the weights bounded when the input does not excite all directions of the weights.
+//Use NewFiltLeakyLMS to make instance.
+type FiltLeakyLMS struct {
+	filtBase
+	gamma float64
+}
+
+//NewFiltLeakyLMS is constructor of leaky LMS filter.
+//This func initialize filter length `n`, update step size `mu`, leakage factor `gamma` and filter weight `w`.
+//`gamma` must be in the range [0, 1). Typical value is a small value such as 1e-3.
+func NewFiltLeakyLMS(n int, mu, gamma float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindLeakyLMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltLeakyLMS{filtBase: base}
+	p.gamma, err = p.checkLeakage(gamma)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` with leakage.
+func (af *FiltLeakyLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` with leakage.
+func (af *FiltLeakyLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.Scale(1-af.gamma, w)
+	floats.AddScaled(w, af.mu*e, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` with leakage.
+func (af *FiltLeakyLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLeakyLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltLeakyNLMS is base struct for leaky NLMS filter.
+//The weights decay by the leakage factor `gamma` in every update:
+//w = (1 - gamma) * w + mu / (eps + x·x) * e * x.
+//Use NewFiltLeakyNLMS to make instance.
+type FiltLeakyNLMS struct {
+	filtBase
+	eps   float64
+	gamma float64
+}
+
+//NewFiltLeakyNLMS is constructor of leaky NLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//leakage factor `gamma` and filter weight `w`.
+//`gamma` must be in the range [0, 1). Typical value is a small value such as 1e-3.
+func NewFiltLeakyNLMS(n int, mu, eps, gamma float64, w []float64) (AdaptiveFilter, error) {
+	base, eps, err := newNLMSBase(kindLeakyNLMS, n, mu, eps, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltLeakyNLMS{filtBase: base, eps: eps}
+	p.gamma, err = p.checkLeakage(gamma)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` with leakage.
+func (af *FiltLeakyNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` with leakage.
+func (af *FiltLeakyNLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	nu := af.mu / (af.eps + floats.Dot(x, x))
+	floats.Scale(1-af.gamma, w)
+	floats.AddScaled(w, nu*e, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` with leakage.
+func (af *FiltLeakyNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLeakyNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//checkLeakage checks that leakage factor `gamma` is in the range [0, 1).
+//With `gamma` = 1 the weights would be forgotten in every update.
+func (af *filtBase) checkLeakage(gamma float64) (float64, error) {
+	gamma, err := af.checkFloatParam(gamma, 0, 1, "gamma")
+	if err != nil {
+		return 0, err
+	}
+	if gamma == 1 {
+		return 0, fmt.Errorf("parameter gamma is not in range <%v, %v)", 0, 1)
+	}
+	return gamma, nil
+}
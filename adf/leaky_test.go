@@ -0,0 +1,95 @@
+package adf
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//newRankDeficientData makes the input of a single sinusoid, whose correlation matrix has rank 2,
+//and the desired values of the system `h` with noise.
+func newRankDeficientData(n int, h []float64, noise float64) (d []float64, x [][]float64) {
+	L := len(h)
+	x = make([][]float64, n)
+	d = make([]float64, n)
+	for i := range x {
+		x[i] = make([]float64, L)
+		for j := range x[i] {
+			x[i][j] = math.Sin(2 * math.Pi * 0.05 * float64(i+j))
+		}
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*noise
+	}
+	return d, x
+}
+
+func TestLeaky_rankDeficient(t *testing.T) {
+	rand.Seed(1)
+	L := 8
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0, 0, 0, 0}
+	d, x := newRankDeficientData(20000, h, 0.01)
+	//the weights which the input cannot see, e.g. after a change of the input spectrum
+	w0 := make([]float64, L)
+	for i := range w0 {
+		w0[i] = 100 * rand.NormFloat64()
+	}
+
+	tests := []struct {
+		name  string
+		plain AdaptiveFilter
+		leaky AdaptiveFilter
+	}{
+		{
+			name:  "LMS",
+			plain: Must(NewFiltLMS(L, 0.05, append([]float64{}, w0...))),
+			leaky: Must(NewFiltLeakyLMS(L, 0.05, 1e-3, append([]float64{}, w0...))),
+		},
+		{
+			name:  "NLMS",
+			plain: Must(NewFiltNLMS(L, 0.5, 1e-3, append([]float64{}, w0...))),
+			leaky: Must(NewFiltLeakyNLMS(L, 0.5, 1e-3, 1e-3, append([]float64{}, w0...))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := tt.plain.Run(d, x, WithoutHistory())
+			check(err)
+			_, _, wPlain := tt.plain.GetParams()
+			//the weights in the directions without excitation are never corrected
+			if floats.Norm(wPlain, math.Inf(1)) < 10 {
+				t.Fatalf("%s w = %v, want the weights left large", tt.name, wPlain)
+			}
+
+			norms := make([]float64, len(d))
+			_, e, _, err := tt.leaky.Run(d, x, WithHistoryFunc(func(i int, w []float64) error {
+				norms[i] = floats.Norm(w, 2)
+				return nil
+			}))
+			check(err)
+			//the norm of the weights never grows beyond the initial weights
+			if max := floats.Max(norms); max > floats.Norm(w0, 2)+1e-9 {
+				t.Errorf("leaky %s max ||w|| = %v, want <= %v", tt.name, max, floats.Norm(w0, 2))
+			}
+			_, _, w := tt.leaky.GetParams()
+			if floats.Norm(w, math.Inf(1)) > 1 {
+				t.Errorf("leaky %s w = %v, want bounded weights", tt.name, w)
+			}
+			//the output still follows the desired signal
+			if mse := floats.Dot(e[len(e)-1000:], e[len(e)-1000:]) / 1000; mse > 1e-2 {
+				t.Errorf("leaky %s MSE = %v, want small error", tt.name, mse)
+			}
+		})
+	}
+}
+
+func TestNewFiltLeaky_invalidGamma(t *testing.T) {
+	for _, gamma := range []float64{-0.1, 1, 1.5} {
+		if _, err := NewFiltLeakyLMS(4, 0.1, gamma, nil); err == nil {
+			t.Errorf("NewFiltLeakyLMS() with gamma = %v: error = nil, want error", gamma)
+		}
+		if _, err := NewFiltLeakyNLMS(4, 0.1, 1e-3, gamma, nil); err == nil {
+			t.Errorf("NewFiltLeakyNLMS() with gamma = %v: error = nil, want error", gamma)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindLMF is the kind name of FiltLMF.
+const kindLMF = "LMF filter"
+
+//FiltLMF is base struct for LMF filter (Least Mean Fourth filter).
+//The weights are updated with the cube of the error: w += mu * e^3 * x.
+//Use NewFiltLMF to make instance.
+type FiltLMF struct {
+	filtBase
+}
+
+//NewFiltLMF is constructor of LMF filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+//The stable range of `mu` also depends on the power of the error,
+//so a small value is recommended when the initial error is large.
+func NewFiltLMF(n int, mu float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltLMF)
+	p.kind = kindLMF
+	p.n = n
+	p.muMin = 0
+	p.muMax = 1
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltLMF) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltLMF) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.AddScaled(w, af.mu*e*e*e, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltLMF) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLMF) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
@@ -0,0 +1,183 @@
+package adf
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestFiltLMF_Run(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 64
+	L := 4
+	//input value
+	var x = make([][]float64, n)
+	//noise
+	var v = make([]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		v[i] = rand.NormFloat64() * 0.1
+		d[i] = x[i][0]
+	}
+	type args struct {
+		d []float64
+		x [][]float64
+	}
+	tests := []struct {
+		name string
+		//fields  fields
+		args    args
+		want    []float64
+		want1   []float64
+		want2   [][]float64
+		wantErr bool
+	}{
+		{
+			name: "Run LMF Filter",
+			args: args{
+				d: d,
+				x: x,
+			},
+			want:    []float64{0, 0, 0, 0, -0.03847336073753953, 0.05151164006036364, -0.0782552768238962, -0.05923241133599422, 0.18540083101824326, 0.7104197984505781, 0.4398889626381123, 0.5392231931811029, 0.44546936047891195, 0.04081473082110966, 0.5306588347003371, -0.005765735449748011, -0.6387158852808406, -0.02607783585210903, -0.31274921120177435, 0.11527359209837373, 0.16171600701995809, 0.156230660528767, -0.5232493222837448, 0.13369163666143263, -0.32541418620848045, 0.6166743635285322, 0.5764330835817011, 0.269808874913197, 0.06041442518206577, -0.024897113043668262, -0.7984941691402547, 0.5926019764271815, -0.396565973173909, -0.3971987392178787, 0.8865660783276973, -0.08939641630671631, -0.13182484330571032, 0.8912340257347733, -0.31787284658369364, -0.7081648923456063, -0.45141467286522696, -1.1096589157928336, 1.463468640915694, -0.08824883628668437, 0.7967961468154311, -0.5579793064810096, -1.1314024750657041, 0.7509266067579657, -1.4192910605225422, 0.4302146939197799, 0.21826166138457745, 1.8915641101388294, -1.0221910237397391, -0.22649043448326597, -0.05611819778972859, 1.8723939448987181, 0.1427875696595204, -0.059387170518945404, -0.234943817521589, -0.467693282834555, 2.2030610983846333, 0.07585732598098906, 0.598744986427209, 0.6096670488933432,},
+			want1:   []float64{0, 0, 0, -1.233758177597947, -0.48252121041561075, 0.27129361255121626, 0.23706301700033183, -0.6720506048414848, 1.4000031312623797, 0.5884210490668561, 0.2925529631664009, 0.16089770925888192, 0.5541567605323505, -0.3573519737151979, 0.5700703590496836, 0.9954761556582796, -0.7963310368513876, 0.16342820942545982, -0.5333451622538228, 0.0408529031844409, 0.1180277116432134, 0.5465196010334142, -0.5462960800692757, 0.1965977489512291, -0.7952999061481527, 0.310624107235551, 0.39660129826148416, 0.24030723557281913, 0.19112561887708343, 0.19158783196196583, -0.8401469087161797, 0.5616288372828557, -0.3739949746758848, -0.3790144706558884, 0.5337134184611828, -0.24106034616825328, -0.11520987352452572, 0.8443992309197883, 0.11203891932683374, -0.3435004052583377, -0.2261088733979123, -0.8519789952920749, 0.5224506410654381, 0.04711072821769188, 0.4069354676709861, -0.28061345603834054, -0.27169025330796104, 0.22335655713065583, -0.5736713094689403, -0.048980953619829404, 0.19130953317487223, 0.6386743332135771, -0.24805169475575406, -0.1941373781380575, 0.05827886639236623, 0.5104664005332227, 0.048478548141087235, 0.06939336850378433, -0.22336903068465025, -0.008764296131633986, 0.528060772639813, -0.023386687333719472, 0.20113141572635662, 0.11567043905036811,},
+			want2:   [][]float64{{0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0.11584843771609012, 0.048920775742428806, -0.03031103248645375, -0.01491185949622794,}, {0.11877496399345158, 0.047107516820848995, -0.031203085974930725, -0.010804102752631387,}, {0.11909724098673986, 0.047266064677802706, -0.031933172272442245, -0.009221293247589309,}, {0.11920302817928997, 0.0467789323934755, -0.03087708121516035, -0.008356091547246385,}, {0.13030145493872755, 0.02271781054291241, -0.05058913482429215, -0.019472106673064565,}, {0.34782033807573387, 0.20091997052635477, 0.04990257167714237, 0.07658512566824238,}, {0.36105127328137376, 0.20838115538263374, 0.05703451113993575, 0.08676804258392527,}, {0.3619682466104501, 0.2092576647304037, 0.058285983044699376, 0.0863717569547617,}, {0.36211405839701255, 0.20946585344479746, 0.058220058915396715, 0.08660100206043247,}, {0.3706196692280435, 0.2067725038563924, 0.06758593477198276, 0.09502224225491017,}, {0.3713419122839449, 0.2042609699548986, 0.06532771241617603, 0.09829658894766079,}, {0.38153805417224745, 0.2134287361321528, 0.0520347588745486, 0.09956887635978451,}, {0.4303550144070311, 0.14264578073190837, 0.05880949579562719, 0.05783570417073814,}, {0.4665890772895879, 0.13917776725008796, 0.08017286465803065, 0.05389360502369056,}, {0.4666190537890784, 0.13899310850548477, 0.08020693901607238, 0.053954658643144374,}, {0.4730372633296978, 0.137808781327566, 0.07808488991566367, 0.04862381308390738,}, {0.473037795579354, 0.137809734999765, 0.0780872856562741, 0.048620166904752064,}, {0.4730607931683923, 0.13786750774186335, 0.07799935887193504, 0.04864731982739972,}, {0.478796517207803, 0.12913806627302568, 0.08069512274800884, 0.03950024850999185,}, {0.48751525229802817, 0.1264456086644617, 0.08983097547725512, 0.03194108393560717,}, {0.4876407397335295, 0.12601981376003518, 0.0901832856395228, 0.0323107706241625,}, {0.5158283092914215, 0.10269692496631436, 0.06571007297316746, 0.01948061755926264,}, {0.5172179259813128, 0.10415507987487697, 0.06647451493361807, 0.019857566572359295,}, {0.5202529391870468, 0.10574619439320475, 0.06725909908576727, 0.020377495305228963,}, {0.5206068872878624, 0.10592072744370767, 0.06737475876044116, 0.019240511223559516,}, {0.5206946952404946, 0.10597891607449107, 0.06680273962653464, 0.019643431740632312,}, {0.52075330705854, 0.10540273682875917, 0.0672085904553897, 0.0193724869732136,}, {0.5693402465180749, 0.07117892575390175, 0.09005630166499477, 0.042387791999719364,}, {0.5795640116786364, 0.06435357151873483, 0.08318088170738079, 0.054968121570766304,}, {0.5815794722178093, 0.06638381597746423, 0.07946603307877843, 0.055832456269442166,}, {0.5836925642300036, 0.0625173766775764, 0.08036563841437305, 0.0565049610403331,}, {0.5944886962697246, 0.06000543811255127, 0.07848782533087492, 0.06969822747397428,}, {0.5947201488227958, 0.06017846170413815, 0.07727218445417018, 0.06984239395316955,}, {0.5947390373064523, 0.06004575371546914, 0.07728792269056393, 0.06992280517245388,}, {0.6469872519224926, 0.053849482028841, 0.0456293715751258, 0.04952713948855838,}, {0.6469727777515234, 0.053775529286827974, 0.04558172835238307, 0.04938919778534543,}, {0.6491040027868734, 0.055148547058855746, 0.04955703478868065, 0.04536468456656244,}, {0.6494956068738363, 0.05628236067663942, 0.04840918670060813, 0.04538846211841077,}, {0.7101518508467863, -0.005124692988943587, 0.04968122732824788, 0.008167608018043275,}, {0.7243119859203502, -0.005418018690654225, 0.05826415534959368, 0.0021882175681247323,}, {0.7243117708533531, -0.005411725670487486, 0.058259771248434664, 0.002180882302747302,}, {0.7283675687195821, -0.008237241504689593, 0.053532255226279454, 0.00546358712635387,}, {0.7292940712119995, -0.0066870624809337075, 0.05245583789952923, 0.007665471861852867,}, {0.7307010208572148, -0.00766402382073139, 0.05445427866020302, 0.007283190160285766,}, {0.7312438352095677, -0.008774387438032206, 0.05466668009903118, 0.007511379593252927,}, {0.7500568542175908, -0.012373129547143568, 0.05080044018180756, -0.016373378416414435,}, {0.7500546142432521, -0.01237553602083326, 0.05078557353023768, -0.016365914987046877,}, {0.7501980010671632, -0.01148972456985958, 0.050340874119193864, -0.016513172623573935,}, {0.7831566836644556, -0.028035804126277028, 0.044861810039600034, -0.016485027928017922,}, {0.7841260396898866, -0.02771481184764283, 0.04486016117588412, -0.018303452076389354,}, {0.7842799243998101, -0.02771560231810432, 0.04398840290873616, -0.018373425887375644,}, {0.7842799457839335, -0.027692019164434502, 0.04399029586828646, -0.018373326856097886,}, {0.8001277858299056, -0.02641995349473255, 0.04405684472224513, -0.021421457109853825,}, {0.8001288754042333, -0.026419896493019086, 0.044054233878677046, -0.021424171317431413,}, {0.8001290425875761, -0.026427553974327975, 0.04404627323559298, -0.021378539791364618,}, {0.8003844308394427, -0.026162054831860106, 0.042524394800359425, -0.021407778301109428,}, {0.8003844468772641, -0.026162146762908782, 0.04252439303416917, -0.02140780522538448,}, {0.8204921657601449, -0.025775834873015428, 0.04841343445930377, -0.016067552028511813,}, {0.8204921322024221, -0.02577634643588766, 0.04841297056797297, -0.016066823337936555,}, {0.8208175434953414, -0.025481259583570042, 0.04794944173290088, -0.016479803028923178,},},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			af := Must(NewFiltLMF(L, 0.05, make([]float64, L)))
+			y, e, wHist, err := af.Run(tt.args.d, tt.args.x)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(y, tt.want) {
+				t.Errorf("Run() y = %v, want %v", y, tt.want)
+				for _, v := range y {
+					fmt.Printf("%g, ", v)
+				}
+				fmt.Printf("\n")
+			}
+			if !reflect.DeepEqual(e, tt.want1) {
+				t.Errorf("Run() e = %v, want %v", e, tt.want1)
+				for _, v := range e {
+					fmt.Printf("%g, ", v)
+				}
+				fmt.Printf("\n")
+			}
+			if !reflect.DeepEqual(wHist, tt.want2) {
+				t.Errorf("Run() wHist = %v, want %v", wHist, tt.want2)
+				for _, v := range wHist {
+					fmt.Printf("{")
+					for _, vv := range v {
+						fmt.Printf("%g, ", vv)
+					}
+					fmt.Printf("}, ")
+				}
+				fmt.Printf("\n")
+			}
+		})
+	}
+}
+
+func TestNewFiltLMF_invalidParams(t *testing.T) {
+	for _, mu := range []float64{-0.1, 1.1} {
+		if _, err := NewFiltLMF(4, mu, nil); err == nil {
+			t.Errorf("NewFiltLMF() with mu = %v: error = nil, want error", mu)
+		}
+	}
+}
+
+func ExampleFiltLMF_Run() {
+	rand.Seed(1)
+
+	//filter coefficients
+	const (
+		//number of samples
+		n = 256
+		//length of filter
+		L = 8
+		//step size
+		mu = 0.1
+	)
+	//input value
+	var x = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = make([]float64, L)
+	}
+	//desired value
+	var d = make([]float64, n)
+
+	//create data
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, 0.2*rand.NormFloat64()+math.Sin(2*math.Pi*1200*float64(i)/48000))
+		copy(x[i], xRow)
+		//input value + noise
+		d[i] = x[i][0] * rand.NormFloat64() * 0.1
+	}
+
+	//make filter instance
+	af := Must(NewFiltLMF(L, mu, nil))
+
+	y, e, w, err := af.Run(d, x)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	//print result of filtering (only the last value)
+	fmt.Println(y[n-1], e[n-1], w[n-1])
+	//output:
+	//-0.023688229145004264 -0.015635977679638938 [-0.005789627832255097 -0.0029119899755869795 -0.00371103592075246 -0.002676093546513951 -0.003643364618381128 -0.0018301360330421578 -0.00320409155512732 -0.0016191328621401578]
+}
+
+func ExampleExploreLearning_lmf() {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 64
+	L := 4
+	mu := 0.1
+	//input value
+	var x = make([][]float64, n)
+	//noise
+	var v = make([]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		v[i] = rand.NormFloat64() * 0.1
+		d[i] = x[i][0]
+	}
+
+	af, err := NewFiltLMF(L, mu, nil)
+	check(err)
+	es, mus, err := ExploreLearning(af, d, x, 0.00001, 1.0, 100, 0.5, 100, "MSE", nil)
+	check(err)
+
+	res := make(map[float64]float64, len(es))
+	for i := 0; i < len(es); i++ {
+		res[es[i]] = mus[i]
+	}
+	eMin := floats.Min(es)
+	fmt.Printf("the step size mu with the smallest error is %.3f\n", res[eMin])
+	//output:
+	//the step size mu with the smallest error is 0.495
+}
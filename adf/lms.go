@@ -1,34 +1,47 @@
 package adf
 
 import (
-	"errors"
+	"encoding/json"
 
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
 )
 
+//kindLMS is the kind name of FiltLMS.
+const kindLMS = "LMS filter"
+
 //FiltLMS is base struct for LMS filter.
 //Use NewFiltLMS to make instance.
 type FiltLMS struct {
 	filtBase
-	wHistory [][]float64
 }
 
 //NewFiltLMS is constructor of LMS filter.
 //This func initialize filter length `n`, update step size `mu` and filter weight `w`.
 func NewFiltLMS(n int, mu float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindLMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltLMS{filtBase: base}, nil
+}
+
+//newLMSBase initializes the common part of LMS and the filters with the step size range of LMS,
+//such as the sign LMS filters. It checks update step size `mu`.
+func newLMSBase(kind string, n int, mu float64, w []float64) (filtBase, error) {
 	var err error
-	p := new(FiltLMS)
-	p.kind = "LMS filter"
+	var p filtBase
+	p.kind = kind
 	p.n = n
 	p.muMin = 0
 	p.muMax = 2
 	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
 	if err != nil {
-		return nil, err
+		return p, err
 	}
 	err = p.initWeights(w, n)
 	if err != nil {
-		return nil, err
+		return p, err
 	}
 	return p, nil
 }
@@ -36,45 +49,84 @@ func NewFiltLMS(n int, mu float64, w []float64) (AdaptiveFilter, error) {
 //Adapt calculates the error `e` between desired value `d` and estimated value `y`,
 //and update filter weights according to error `e`.
 func (af *FiltLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltLMS) Step(d float64, x []float64) (y, e float64) {
 	w := af.w.RawRowView(0)
-	y := floats.Dot(w, x)
-	e := d - y
+	y = floats.Dot(w, x)
+	e = d - y
 	for i := 0; i < len(x); i++ {
 		w[i] += af.mu * e * x[i]
 	}
+	return y, e
 }
 
 //Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 //while updating filter weights according to error `e`.
-func (af *FiltLMS) Run(d []float64, x [][]float64) (y []float64, e []float64, wHist [][]float64, err error) {
-	//measure the data and check if the dimension agree
-	N := len(x)
-	if len(d) != N {
-		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+func (af *FiltLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//state returns the complete state of the filter.
+func (af *FiltLMS) state() *filterState {
+	return &filterState{
+		Version: stateVersion,
+		Kind:    kindLMS,
+		N:       af.n,
+		Mu:      af.mu,
+		W:       denseData(af.w),
+	}
+}
+
+//setState restores the filter from the state `s`.
+func (af *FiltLMS) setState(s *filterState) error {
+	if err := checkState(s, kindLMS); err != nil {
+		return err
 	}
-	af.n = len(x[0])
-	af.wHistory = make([][]float64, N)
-	for i := 0; i < N; i++ {
-		af.wHistory[i] = make([]float64, af.n)
+	p, err := NewFiltLMS(s.N, s.Mu, s.W)
+	if err != nil {
+		return err
 	}
+	*af = *p.(*FiltLMS)
+	return nil
+}
+
+//MarshalBinary implements encoding.BinaryMarshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltLMS) MarshalBinary() ([]byte, error) {
+	return encodeState(af.state())
+}
 
-	y = make([]float64, N)
-	e = make([]float64, N)
-	//adaptation loop
-	for i := 0; i < N; i++ {
-		w := af.w.RawRowView(0)
-		copy(af.wHistory[i], w)
-		y[i] = floats.Dot(w, x[i])
-		e[i] = d[i] - y[i]
-		for j := 0; j < af.n; j++ {
-			w[j] += af.mu * e[i] * x[i][j]
-		}
+//UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (af *FiltLMS) UnmarshalBinary(data []byte) error {
+	s, err := decodeState(data)
+	if err != nil {
+		return err
 	}
-	wHist = af.wHistory
-	return y, e, wHist, nil
+	return af.setState(s)
 }
 
-func (af *FiltLMS) clone() AdaptiveFilter {
-	altaf := *af
-	return &altaf
+//MarshalJSON implements json.Marshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltLMS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(af.state())
+}
+
+//UnmarshalJSON implements json.Unmarshaler.
+func (af *FiltLMS) UnmarshalJSON(data []byte) error {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
 }
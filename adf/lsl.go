@@ -0,0 +1,246 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindLSL is the kind name of FiltLSL.
+const kindLSL = "LSL filter"
+
+//FiltLSL is base struct for least-squares lattice filter, the lattice RLS based on a posteriori errors.
+//It is the joint-process estimator which decomposes the input into the orthogonal backward prediction errors
+//by the lattice of `n-1` stages and estimates the desired value from them by the ladder.
+//The estimation errors of all the orders from 0 to `n` are obtained at once,
+//and the error of order `n` agrees with FiltRLS of the same length after the initialization is forgotten.
+//
+//The weights `w` are the ladder (joint-process) coefficients on the backward prediction errors of the orders 0 to `n-1`,
+//not the transversal weights of FiltRLS.
+//Because the lattice uses only the newest sample x[n-1] of each input,
+//the rows of `x` must be the tap-delay line, that is x[i+1] is x[i] shifted by one sample.
+//Use NewFiltLSL to make instance.
+type FiltLSL struct {
+	filtBase
+	eps float64
+	//the quantities of the previous sample. The index is the order.
+	delta  []float64
+	deltaD []float64
+	xif    []float64
+	xib    []float64
+	epsB   []float64
+	gamma  []float64
+	//the workspace for the quantities of the current sample.
+	deltaN []float64
+	xifN   []float64
+	xibN   []float64
+	epsBN  []float64
+	gammaN []float64
+	//kf and kb are the forward and backward reflection coefficients,
+	//and kfN and kbN are the workspace for them.
+	kf  []float64
+	kb  []float64
+	kfN []float64
+	kbN []float64
+	//orderE is the workspace for the errors of every order.
+	orderE []float64
+}
+
+//NewFiltLSL is constructor of LSL filter.
+//This func initialize filter length `n`, forgetting factor `mu`, the initial prediction error energy `eps`,
+//and the ladder coefficients `w`. `mu` and `eps` correspond to the parameters of NewFiltRLS.
+//Like the initial weights of FiltRLS, `w` is the starting point of the ladder which is forgotten as the samples come.
+func NewFiltLSL(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltLSL)
+	p.kind = kindLSL
+	p.n = n
+	p.muMin = math.SmallestNonzeroFloat64
+	p.muMax = 1
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, math.SmallestNonzeroFloat64, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.delta = make([]float64, n-1)
+	p.deltaD = make([]float64, n)
+	p.xif = make([]float64, n)
+	p.xib = make([]float64, n)
+	p.epsB = make([]float64, n)
+	p.gamma = make([]float64, n+1)
+	p.deltaN = make([]float64, n-1)
+	p.xifN = make([]float64, n)
+	p.xibN = make([]float64, n)
+	p.epsBN = make([]float64, n)
+	p.gammaN = make([]float64, n+1)
+	p.kf = make([]float64, n-1)
+	p.kb = make([]float64, n-1)
+	p.kfN = make([]float64, n-1)
+	p.kbN = make([]float64, n-1)
+	p.orderE = make([]float64, n+1)
+	p.initLattice()
+	return p, nil
+}
+
+//ReflectionCoefficients returns the forward and backward reflection coefficients of the `n-1` stages.
+//The slices are only valid until the next update.
+func (af *FiltLSL) ReflectionCoefficients() (kf, kb []float64) {
+	return af.kf, af.kb
+}
+
+//lattice runs the lattice stages with the newest input sample `xk` and sets the backward prediction errors,
+//the conversion factors, the prediction error energies and the reflection coefficients of the current sample
+//to the workspace.
+//The state of the filter is not changed.
+func (af *FiltLSL) lattice(xk float64) {
+	n := af.n
+	lam := af.mu
+	af.gammaN[0] = 1
+	af.epsBN[0] = xk
+	af.xifN[0] = xk*xk + lam*af.xif[0]
+	af.xibN[0] = af.xifN[0]
+	ef := xk
+	for i := 0; i < n-1; i++ {
+		af.deltaN[i] = lam*af.delta[i] + af.epsB[i]*ef/af.gamma[i]
+		af.gammaN[i+1] = af.gammaN[i] - af.epsBN[i]*af.epsBN[i]/af.xibN[i]
+		af.kbN[i] = af.deltaN[i] / af.xifN[i]
+		af.kfN[i] = af.deltaN[i] / af.xib[i]
+		af.epsBN[i+1] = af.epsB[i] - af.kbN[i]*ef
+		ef -= af.kfN[i] * af.epsB[i]
+		af.xibN[i+1] = af.xib[i] - af.deltaN[i]*af.kbN[i]
+		af.xifN[i+1] = af.xifN[i] - af.deltaN[i]*af.kfN[i]
+	}
+	af.gammaN[n] = af.gammaN[n-1] - af.epsBN[n-1]*af.epsBN[n-1]/af.xibN[n-1]
+}
+
+//Predict calculates the new estimated value `y` from input slice `x`.
+//It is the sum of the a priori backward prediction errors weighted by the ladder coefficients.
+func (af *FiltLSL) Predict(x []float64) (y float64) {
+	af.lattice(x[af.n-1])
+	w := af.w.RawRowView(0)
+	for i := 0; i < af.n; i++ {
+		y += w[i] * af.epsBN[i] / af.gammaN[i]
+	}
+	return y
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltLSL) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update the lattice and the ladder coefficients.
+func (af *FiltLSL) Step(d float64, x []float64) (y, e float64) {
+	af.lattice(x[af.n-1])
+	lam := af.mu
+	w := af.w.RawRowView(0)
+	//joint process estimation with the a posteriori error of each order
+	epsilon := d
+	af.orderE[0] = d
+	for i := 0; i < af.n; i++ {
+		af.deltaD[i] = lam*af.deltaD[i] + epsilon*af.epsBN[i]/af.gammaN[i]
+		w[i] = af.deltaD[i] / af.xibN[i]
+		epsilon -= w[i] * af.epsBN[i]
+		af.orderE[i+1] = epsilon / af.gammaN[i+1]
+	}
+	af.delta, af.deltaN = af.deltaN, af.delta
+	af.xif, af.xifN = af.xifN, af.xif
+	af.xib, af.xibN = af.xibN, af.xib
+	af.epsB, af.epsBN = af.epsBN, af.epsB
+	af.gamma, af.gammaN = af.gammaN, af.gamma
+	af.kf, af.kfN = af.kfN, af.kf
+	af.kb, af.kbN = af.kbN, af.kb
+	e = af.orderE[af.n]
+	return d - e, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating the lattice and the ladder coefficients.
+//The errors of every order and the reflection coefficients are reported by WithLatticeReport.
+func (af *FiltLSL) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, &runHooks{
+		init: func(N int, hist *historyRecorder[float64]) {
+			hist.initLattice(N, af.n)
+		},
+		after: func(i int, hist *historyRecorder[float64]) error {
+			hist.recordLattice(i, af.orderE, af.kf, af.kb)
+			return nil
+		},
+	})
+}
+
+//SetWeights sets the ladder coefficients.
+//The lattice is kept and the ladder continues from `w`.
+func (af *FiltLSL) SetWeights(w []float64) error {
+	if err := af.filtBase.SetWeights(w); err != nil {
+		return err
+	}
+	af.seedLadder()
+	return nil
+}
+
+//Reset sets the ladder coefficients to zeros and initializes the lattice.
+func (af *FiltLSL) Reset() {
+	af.filtBase.Reset()
+	af.initLattice()
+}
+
+//initLattice initializes the lattice to the state before the first sample.
+//The prediction error energies are set to `eps` and the conversion factors to 1.
+//The ladder is seeded from the current ladder coefficients.
+func (af *FiltLSL) initLattice() {
+	for i := range af.delta {
+		af.delta[i] = 0
+		af.kf[i] = 0
+		af.kb[i] = 0
+	}
+	for i := 0; i < af.n; i++ {
+		af.xif[i] = af.eps
+		af.xib[i] = af.eps
+		af.epsB[i] = 0
+	}
+	for i := range af.gamma {
+		af.gamma[i] = 1
+	}
+	af.seedLadder()
+}
+
+//seedLadder sets the ladder cross-correlations so that the ladder coefficients are the current weights `w`.
+func (af *FiltLSL) seedLadder() {
+	w := af.w.RawRowView(0)
+	for i := 0; i < af.n; i++ {
+		af.deltaD[i] = w[i] * af.xib[i]
+	}
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLSL) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.delta = append([]float64{}, af.delta...)
+	altaf.deltaD = append([]float64{}, af.deltaD...)
+	altaf.xif = append([]float64{}, af.xif...)
+	altaf.xib = append([]float64{}, af.xib...)
+	altaf.epsB = append([]float64{}, af.epsB...)
+	altaf.gamma = append([]float64{}, af.gamma...)
+	altaf.deltaN = make([]float64, len(af.deltaN))
+	altaf.xifN = make([]float64, len(af.xifN))
+	altaf.xibN = make([]float64, len(af.xibN))
+	altaf.epsBN = make([]float64, len(af.epsBN))
+	altaf.gammaN = make([]float64, len(af.gammaN))
+	altaf.kf = append([]float64{}, af.kf...)
+	altaf.kb = append([]float64{}, af.kb...)
+	altaf.kfN = make([]float64, len(af.kfN))
+	altaf.kbN = make([]float64, len(af.kbN))
+	altaf.orderE = make([]float64, len(af.orderE))
+	return &altaf
+}
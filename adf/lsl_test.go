@@ -0,0 +1,156 @@
+package adf
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestFiltLSL_agreesWithRLS(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	d, x := newSystemData(4000, h, 0.01)
+	af := Must(NewFiltLSL(len(h), 0.99, 0.01, nil))
+	want := Must(NewFiltIQRRLS(len(h), 0.99, 0.01, nil))
+	y, e, _, err := af.Run(d, x)
+	check(err)
+	wantY, wantE, _, err := want.Run(d, x)
+	check(err)
+	//the initialization differs, but its effect is forgotten
+	for i := 2000; i < len(d); i++ {
+		if !floats.EqualWithinAbs(y[i], wantY[i], 1e-9) || !floats.EqualWithinAbs(e[i], wantE[i], 1e-9) {
+			t.Fatalf("Run() y[%d], e[%d] = %v, %v, want %v, %v", i, i, y[i], e[i], wantY[i], wantE[i])
+		}
+	}
+}
+
+func TestFiltLSL_Run_latticeReport(t *testing.T) {
+	rand.Seed(1)
+	N := 4000
+	L := 6
+	//the input is the AR(1) process, whose first reflection coefficient is `a` and the others are 0
+	const a = 0.8
+	h := []float64{0, 0, 0, 0.1, -0.3, 0.5}
+	x := make([][]float64, N)
+	d := make([]float64, N)
+	xRow := make([]float64, L)
+	for i := range x {
+		v := a*xRow[L-1] + rand.NormFloat64()
+		copy(xRow, xRow[1:])
+		xRow[L-1] = v
+		x[i] = append([]float64{}, xRow...)
+		d[i] = floats.Dot(h, x[i]) + 0.01*rand.NormFloat64()
+	}
+
+	af := Must(NewFiltLSL(L, 0.99, 0.01, nil))
+	var r LatticeReport
+	_, e, _, err := af.Run(d, x, WithLatticeReport(&r))
+	check(err)
+	if len(r.OrderErrors) != N || len(r.Forward) != N || len(r.Backward) != N {
+		t.Fatalf("LatticeReport has %d, %d, %d rows, want %d", len(r.OrderErrors), len(r.Forward), len(r.Backward), N)
+	}
+	for i := range d {
+		if len(r.OrderErrors[i]) != L+1 || len(r.Forward[i]) != L-1 || len(r.Backward[i]) != L-1 {
+			t.Fatalf("LatticeReport row %d has %d, %d, %d values, want %d, %d, %d",
+				i, len(r.OrderErrors[i]), len(r.Forward[i]), len(r.Backward[i]), L+1, L-1, L-1)
+		}
+		if r.OrderErrors[i][0] != d[i] || r.OrderErrors[i][L] != e[i] {
+			t.Fatalf("OrderErrors[%d] = %v, want d = %v at order 0 and e = %v at order %d", i, r.OrderErrors[i], d[i], e[i], L)
+		}
+	}
+
+	//the error decreases until the order covers the system, which has 3 taps from the newest sample
+	mse := make([]float64, L+1)
+	for i := N - 1000; i < N; i++ {
+		for m, v := range r.OrderErrors[i] {
+			mse[m] += v * v / 1000
+		}
+	}
+	for m := 1; m <= 2; m++ {
+		if mse[m] < 10*mse[3] {
+			t.Errorf("MSE of order %d = %v, want much larger than MSE of order 3 = %v", m, mse[m], mse[3])
+		}
+	}
+	for m := 3; m <= L; m++ {
+		if mse[m] > 2e-4 {
+			t.Errorf("MSE of order %d = %v, want about the noise power 1e-4", m, mse[m])
+		}
+	}
+
+	kf, kb := af.(*FiltLSL).ReflectionCoefficients()
+	if !floats.Equal(kf, r.Forward[N-1]) || !floats.Equal(kb, r.Backward[N-1]) {
+		t.Errorf("ReflectionCoefficients() = %v, %v, want the last row of the report %v, %v", kf, kb, r.Forward[N-1], r.Backward[N-1])
+	}
+	for i := range kb {
+		want := 0.0
+		if i == 0 {
+			want = a
+		}
+		if math.Abs(kf[i]-want) > 0.1 || math.Abs(kb[i]-want) > 0.1 {
+			t.Errorf("reflection coefficients of stage %d = %v, %v, want about %v", i, kf[i], kb[i], want)
+		}
+	}
+}
+
+func TestFiltLSL_Reset(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	af := Must(NewFiltLSL(4, 0.99, 0.1, nil))
+	_, want, _, err := af.Run(d, x)
+	check(err)
+	af.Reset()
+	_, e, _, err := af.Run(d, x)
+	check(err)
+	if !floats.Equal(e, want) {
+		t.Errorf("Run() after Reset() e = %v, want %v", e, want)
+	}
+}
+
+func TestFiltLSL_Predict_keepsState(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	af := Must(NewFiltLSL(4, 0.99, 0.1, nil))
+	_, _, _, err := af.Run(d[:32], x[:32])
+	check(err)
+	kf, kb := af.(*FiltLSL).ReflectionCoefficients()
+	wantKf, wantKb := append([]float64{}, kf...), append([]float64{}, kb...)
+	af.Predict(x[32])
+	kf, kb = af.(*FiltLSL).ReflectionCoefficients()
+	if !floats.Equal(kf, wantKf) || !floats.Equal(kb, wantKb) {
+		t.Errorf("ReflectionCoefficients() after Predict() = %v, %v, want %v, %v", kf, kb, wantKf, wantKb)
+	}
+}
+
+func TestFiltLSL_SetWeights(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	w := []float64{0.3, 0.2, -0.1, 0.05}
+	//the ladder starts from the given weights, both by the constructor and by SetWeights
+	af := Must(NewFiltLSL(4, 0.99, 0.1, w))
+	af1 := Must(NewFiltLSL(4, 0.99, 0.1, nil))
+	check(af1.SetWeights(w))
+	af0 := Must(NewFiltLSL(4, 0.99, 0.1, nil))
+	y, _, _, err := af.Run(d, x)
+	check(err)
+	y1, _, _, err := af1.Run(d, x)
+	check(err)
+	y0, _, _, err := af0.Run(d, x)
+	check(err)
+	if !floats.Equal(y, y1) {
+		t.Errorf("Run() with SetWeights() y = %v, want %v", y1, y)
+	}
+	if floats.Equal(y, y0) {
+		t.Errorf("Run() with w = %v agrees with zero weights", w)
+	}
+}
+
+func TestNewFiltLSL_invalidParams(t *testing.T) {
+	if _, err := NewFiltLSL(4, 0, 0.1, nil); err == nil {
+		t.Errorf("NewFiltLSL() with mu = 0: error = nil, want error")
+	}
+	if _, err := NewFiltLSL(0, 0.99, 0.1, nil); err == nil {
+		t.Errorf("NewFiltLSL() with n = 0: error = nil, want error")
+	}
+}
@@ -0,0 +1,72 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindNLMF is the kind name of FiltNLMF.
+const kindNLMF = "NLMF filter"
+
+//FiltNLMF is base struct for NLMF filter (Normalized Least Mean Fourth filter).
+//The weights are updated with the cube of the error normalized by the input power:
+//w += mu / (eps + x·x) * e^3 * x.
+//Use NewFiltNLMF to make instance.
+type FiltNLMF struct {
+	filtBase
+	eps float64
+}
+
+//NewFiltNLMF is constructor of NLMF filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps` and filter weight `w`.
+func NewFiltNLMF(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltNLMF)
+	p.kind = kindNLMF
+	p.n = n
+	p.muMin = 0
+	p.muMax = 1
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, 0, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltNLMF) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltNLMF) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	nu := af.mu / (af.eps + floats.Dot(x, x))
+	floats.AddScaled(w, nu*e*e*e, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltNLMF) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltNLMF) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
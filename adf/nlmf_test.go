@@ -0,0 +1,190 @@
+package adf
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestFiltNLMF_Run(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 64
+	L := 4
+	//input value
+	var x = make([][]float64, n)
+	//noise
+	var v = make([]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		v[i] = rand.NormFloat64() * 0.1
+		d[i] = x[i][0]
+	}
+	type args struct {
+		d []float64
+		x [][]float64
+	}
+	tests := []struct {
+		name string
+		//fields  fields
+		args    args
+		want    []float64
+		want1   []float64
+		want2   [][]float64
+		wantErr bool
+	}{
+		{
+			name: "Run NLMF Filter",
+			args: args{
+				d: d,
+				x: x,
+			},
+			want:    []float64{0, 0, 0, 0, -0.20006659534421423, 0.2517898309816514, -0.4229664125105482, -0.27280495961456097, 1.1367941448091765, 0.862264117509566, 0.5262243818243264, 0.704366900116149, 0.411945058476266, -0.054959130463101774, 1.1091768295426898, 0.33136233142471416, -0.9409282194108105, -0.0602305454119646, -0.6486597174280784, 0.14886144205546817, 0.3907867095683179, 0.3640164869791487, -0.720149522753695, -0.020081575905669252, -0.791348515295822, 0.8186059475811012, 0.8058083961667141, 0.48741312965626094, 0.26714496111520775, -0.14482484605274143, -1.0362864000243381, 0.8382322861757965, -0.7652682296556613, -0.4527520623407344, 1.1047646755930722, -0.36163067807345667, -0.0004529325127920203, 1.4575448429508004, -0.22163195103604913, -0.886497107303453, -0.837244369338426, -1.4411693490598307, 1.6430634952914462, 0.1333113209654578, 0.9764059543931355, -0.8174552313002988, -1.0499445493005009, 0.6522231745512084, -1.7554774700533162, 0.316571160845535, 0.5854885466242088, 2.09494923270395, -1.1966681217591828, -0.44510150073722354, 0.15337047032341694, 2.1027142455009327, 0.19506619170591552, -0.07871227493712657, -0.5188633776135191, -0.26078405003649063, 2.3547681241374594, 0.10519645863006427, 0.8121229217522163, 0.6483149161284153,},
+			want1:   []float64{0, 0, 0, -1.233758177597947, -0.32092797580893606, 0.07101542162992847, 0.5817741526869837, -0.45847805656291807, 0.44860981747144657, 0.43657673000786823, 0.20621754398018677, -0.0042459976761641816, 0.5876810625349964, -0.2615781124309865, -0.00844763579266905, 0.6583480887838175, -0.49411870272141767, 0.19758091898531538, -0.19743465602751875, 0.0072650532273464685, -0.11104299090514641, 0.33873377458303255, -0.3493958795993254, 0.350370961518331, -0.32936557706081127, 0.10869252318298206, 0.16722598567647118, 0.022702980829755193, -0.015604917056058543, 0.311515564971039, -0.6023546778320963, 0.31599852753424074, -0.005292718194132462, -0.3234611475330327, 0.31551482119580787, 0.03117391559848709, -0.246581784317444, 0.27808841370376114, 0.015798023779189235, -0.165168190300491, 0.1597208230752868, -0.5204685620250777, 0.34285578668968597, -0.1744494290344503, 0.2273256600932817, -0.021137531219051264, -0.3531481790731643, 0.3220599893374132, -0.23748489993816624, 0.0646625794544155, -0.1759173520647591, 0.4352892106484565, -0.0735745967363104, 0.02447368811590006, -0.1512098017207793, 0.28014609993100814, -0.0038000739053078714, 0.0887184729219655, 0.06055052940727984, -0.21567352892969838, 0.37635374688698686, -0.052725819982794686, -0.012246519598650751, 0.07702257181529604,},
+			want2:   [][]float64{{0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0.6024272916503904, 0.2543945435688363, -0.15762140230748392, -0.07754365364683781,}, {0.6116299989797148, 0.2486925988104143, -0.16042653931925813, -0.0646264680456683,}, {0.6116481898652062, 0.24870154802481934, -0.16046774895107624, -0.06453712655663704,}, {0.6149325634331018, 0.2335775584364286, -0.12767930386416573, -0.0376752208097889,}, {0.6216168707147206, 0.219086141276526, -0.13955138504568418, -0.04437012112842256,}, {0.6353083956837383, 0.23030291146106863, -0.1332260275001649, -0.038323887458551406,}, {0.6498628379003761, 0.23851044797837206, -0.12538067120520416, -0.02712236061689971,}, {0.6513733931579018, 0.23995434581221425, -0.12331908729055456, -0.027775172777988317,}, {0.6513733835918398, 0.2399543321538799, -0.12331908296556604, -0.02777518781773873,}, {0.6822023494980229, 0.2301921664160069, -0.08937204809643876, 0.0027479729523460124,}, {0.6828534382445244, 0.22792806509117167, -0.09140779375151971, 0.005699735889178023,}, {0.6828533605317131, 0.22792799521641996, -0.09140769243547281, 0.005699726192075666,}, {0.7202717197725441, 0.17367262903721098, -0.08621483448905742, -0.026288886407291313,}, {0.7509841446304467, 0.1707330993517923, -0.06810698765375034, -0.029630256769994555,}, {0.7516167178376105, 0.16683637437423499, -0.06738794018940863, -0.02834188472778672,}, {0.754097569572562, 0.16637859247797634, -0.06820818283793599, -0.030402434071134483,}, {0.7540975867717175, 0.16637862329501163, -0.06820810542179344, -0.030402551894042364,}, {0.7539926550203586, 0.16611502196710326, -0.0678069193906537, -0.030526443303841338,}, {0.7585405205422832, 0.15919343271193673, -0.0656694435457549, -0.03777917198904122,}, {0.7653112121321042, 0.15710255600819423, -0.0585748317013028, -0.043649378100388714,}, {0.7675506857276299, 0.14950373592104335, -0.05228743485809071, -0.03705187633194879,}, {0.7735760219927273, 0.14451826715379032, -0.05751879528096012, -0.039794432279879006,}, {0.7738555159478605, 0.14481154621812203, -0.05736504287675373, -0.03971861642985336,}, {0.7756082142833531, 0.14573040342855606, -0.056911951162052866, -0.03941836131961075,}, {0.7756091972170946, 0.14573088811663304, -0.05691162996864484, -0.039421518788219284,}, {0.7756090808896188, 0.14573081102866617, -0.056910872160951885, -0.03942205257502709,}, {0.7761522069914588, 0.14039164909627705, -0.05315005760245643, -0.04193276082892194,}, {0.8104974657265511, 0.11619943490575711, -0.03699940967675016, -0.025663643761757397,}, {0.8145035013470137, 0.11352501782369927, -0.039693444293477216, -0.02073422220450358,}, {0.8145035185389674, 0.11352503514176039, -0.039693475981274894, -0.02073421483169629,}, {0.8192113700240071, 0.1049108234109186, -0.037689205324489494, -0.01923591160303219,}, {0.8235009219281659, 0.10391277236679557, -0.038435303710313055, -0.013993922995975155,}, {0.8234993698085301, 0.10391161207147633, -0.038427151628818414, -0.013994889775646452,}, {0.8239380121899421, 0.10082976869850628, -0.03806166662660564, -0.012127520781893442,}, {0.8279777191408929, 0.10035068779619413, -0.040509430056187075, -0.01370446485621222,}, {0.8279776447592865, 0.10035030775897022, -0.0405096748908966, -0.013705173727587701,}, {0.828230861620638, 0.10051343987549759, -0.040037357513794974, -0.014183337501391453,}, {0.8280636039061049, 0.10002917763020881, -0.039547101016905305, -0.014193493112917847,}, {0.843025256576764, 0.08488232796898193, -0.03923333562677992, -0.02337450160733303,}, {0.8495881282922024, 0.08474637877491231, -0.035255361175667764, -0.026145800910303343,}, {0.8496146165851963, 0.08397131163674795, -0.03471540219751255, -0.025242367742583652,}, {0.8510091447362386, 0.08299979838535554, -0.03634089103962009, -0.02411365658905223,}, {0.8510096662526744, 0.08300067096135616, -0.036341496941204095, -0.024112417176218683,}, {0.8554015843764713, 0.07995099947588535, -0.030103186831111548, -0.02530574341871655,}, {0.8585105304247318, 0.0735914392225942, -0.02888666665063496, -0.023998798175277565,}, {0.859759400035985, 0.07335254297436898, -0.029143320276529643, -0.025584346376642236,}, {0.8597655879191072, 0.07335919080853011, -0.029102251456791, -0.0256049639470405,}, {0.8596322348259829, 0.07253536548281174, -0.02868867062449622, -0.02546801090783213,}, {0.8723685921856963, 0.06614139686738858, -0.030805967573744097, -0.02545713483900494,}, {0.872402461626538, 0.06615261238439846, -0.030806025185282703, -0.025520670843839695,}, {0.8724019383433396, 0.06615261507238424, -0.03080306078125259, -0.025520432898694684,}, {0.8724012847569965, 0.06543181736320412, -0.03086091737356978, -0.02552345969990815,}, {0.8768225957596124, 0.06578670471221751, -0.03084235123589011, -0.02637384257790563,}, {0.8768225846824109, 0.06578670413270728, -0.030842324692643346, -0.026373814983804485,}, {0.8768230271313608, 0.06576643868935389, -0.030863392450430672, -0.026253051632340423,}, {0.8768165865973849, 0.06575974317287991, -0.030825012808071844, -0.026252314278080627,}, {0.8771035407857612, 0.06411488139618832, -0.030856614088419367, -0.026734052619360314,}, {0.8855408594194418, 0.06427698016661545, -0.028385537222852704, -0.024493250563742436,}, {0.8855393005406189, 0.06425321620230312, -0.028407086669906625, -0.02445940022439014,}, {0.8855390903390651, 0.06425302558902013, -0.028406787250422758, -0.02445913345745664,},},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			af := Must(NewFiltNLMF(L, 0.5, 1e-5, nil))
+			got, got1, got2, err := af.Run(tt.args.d, tt.args.x)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() got = %v\n, want %v\n", got, tt.want)
+				for i := 0; i < n; i++ {
+					fmt.Printf("%g, ", got[i])
+				}
+				fmt.Println("")
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Run() got1 = %v\n, want %v\n", got1, tt.want1)
+				for i := 0; i < n; i++ {
+					fmt.Printf("%g, ", got1[i])
+				}
+				fmt.Println("")
+			}
+			if !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("Run() got2 = %v\n, want %v\n", got2, tt.want2)
+				for i := 0; i < n; i++ {
+					fmt.Print("{")
+					for k := 0; k < L; k++ {
+						fmt.Printf("%g, ", got2[i][k])
+					}
+					fmt.Print("}, ")
+				}
+				fmt.Println("")
+			}
+		})
+	}
+}
+
+func TestNewFiltNLMF_invalidParams(t *testing.T) {
+	for _, mu := range []float64{-0.1, 1.1} {
+		if _, err := NewFiltNLMF(4, mu, 1e-5, nil); err == nil {
+			t.Errorf("NewFiltNLMF() with mu = %v: error = nil, want error", mu)
+		}
+	}
+	if _, err := NewFiltNLMF(4, 0.5, 2, nil); err == nil {
+		t.Errorf("NewFiltNLMF() with eps = 2: error = nil, want error")
+	}
+}
+
+func ExampleFiltNLMF_Run() {
+	rand.Seed(1)
+
+	//filter coefficients
+	const (
+		//number of samples
+		n  = 256
+		//length of filter
+		L  = 8
+		//step size
+		mu = 0.5
+		//small value (epsilon)
+		eps = 1e-5
+	)
+	//input value
+	var x = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = make([]float64, L)
+	}
+	//desired value
+	var d = make([]float64, n)
+
+	//create data
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, 0.2*rand.NormFloat64()+math.Sin(2*math.Pi*1200*float64(i)/48000))
+		copy(x[i], xRow)
+		//input value + noise
+		d[i] = x[i][0] * rand.NormFloat64() * 0.1
+	}
+
+	//make filter instance
+	af := Must(NewFiltNLMF(L, mu, eps, nil))
+
+	y, e, w, err := af.Run(d, x)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	//print result of filtering (only the last value)
+	fmt.Println(y[n-1], e[n-1], w[n-1])
+	//output:
+	//-0.022401704230389976 -0.016922502594253225 [-0.006671858508256657 -0.0036161667786248227 -0.0048894413898755915 -0.0017674576715746915 -0.0027621830235949082 -0.001478530050630224 -0.0027570083178303237 -0.0009371219165990943]
+}
+
+func ExampleExploreLearning_nlmf() {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	//n := 64
+	n := 512
+	L := 8
+	mu := 0.5
+	eps := 0.001
+	//input value
+	var x = make([][]float64, n)
+	//noise
+	var v = make([]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		v[i] = rand.NormFloat64() * 0.1
+		d[i] = x[i][L-1]
+	}
+
+	af, err := NewFiltNLMF(L, mu, eps, nil)
+	check(err)
+	es, mus, err := ExploreLearning(af, d, x, 0.00001, 1.0, 100, 0.5, 100, "MSE", nil)
+	check(err)
+
+	res := make(map[float64]float64, len(es))
+	for i := 0; i < len(es); i++ {
+		res[es[i]] = mus[i]
+	}
+	eMin := floats.Min(es)
+	fmt.Printf("the step size mu with the smallest error is %.3f\n", res[eMin])
+	//output:
+	//the step size mu with the smallest error is 0.657
+}
@@ -1,87 +1,139 @@
 package adf
 
 import (
-	"errors"
+	"encoding/json"
 
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
 )
 
+//kindNLMS is the kind name of FiltNLMS.
+const kindNLMS = "NLMS filter"
+
 //FiltNLMS is base struct for NLMS filter.
 //Use NewFiltNLMS to make instance.
 type FiltNLMS struct {
 	filtBase
-	eps      float64
-	wHistory [][]float64
+	eps float64
 }
 
 //NewFiltLMS is constructor of LMS filter.
 //This func initialize filter length `n`, update step size `mu` and filter weight `w`.
 func NewFiltNLMS(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
+	base, eps, err := newNLMSBase(kindNLMS, n, mu, eps, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltNLMS{filtBase: base, eps: eps}, nil
+}
+
+//newNLMSBase initializes the common part of NLMS and the filters normalized in the same way.
+//It checks update step size `mu` and regularization term `eps`, and returns the checked `eps`.
+func newNLMSBase(kind string, n int, mu float64, eps float64, w []float64) (filtBase, float64, error) {
 	var err error
-	p := new(FiltNLMS)
-	p.kind = "NLMS filter"
+	var p filtBase
+	p.kind = kind
 	p.n = n
 	p.muMin = 0
 	p.muMax = 2
 	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
 	if err != nil {
-		return nil, err
+		return p, 0, err
 	}
-	p.eps, err = p.checkFloatParam(eps, 0, 1, "eps")
+	eps, err = p.checkFloatParam(eps, 0, 1, "eps")
 	if err != nil {
-		return nil, err
+		return p, 0, err
 	}
 	err = p.initWeights(w, n)
 	if err != nil {
-		return nil, err
+		return p, 0, err
 	}
-	return p, nil
+	return p, eps, nil
 }
 
 //Adapt calculates the error `e` between desired value `d` and estimated value `y`,
 //and update filter weights according to error `e`.
 func (af *FiltNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltNLMS) Step(d float64, x []float64) (y, e float64) {
 	w := af.w.RawRowView(0)
-	y := floats.Dot(w, x)
-	e := d - y
+	y = floats.Dot(w, x)
+	e = d - y
 	nu := af.mu / (af.eps + floats.Dot(x, x))
 	for i := 0; i < len(x); i++ {
 		w[i] += nu * e * x[i]
 	}
+	return y, e
 }
 
 //Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 //while updating filter weights according to error `e`.
-func (af *FiltNLMS) Run(d []float64, x [][]float64) (y []float64, e []float64, wHist [][]float64, err error) {
-	//measure the data and check if the dimension agree
-	N := len(x)
-	if len(d) != N {
-		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+func (af *FiltNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//state returns the complete state of the filter.
+func (af *FiltNLMS) state() *filterState {
+	return &filterState{
+		Version: stateVersion,
+		Kind:    kindNLMS,
+		N:       af.n,
+		Mu:      af.mu,
+		W:       denseData(af.w),
+		Eps:     af.eps,
+	}
+}
+
+//setState restores the filter from the state `s`.
+func (af *FiltNLMS) setState(s *filterState) error {
+	if err := checkState(s, kindNLMS); err != nil {
+		return err
 	}
-	af.n = len(x[0])
-	af.wHistory = make([][]float64, N)
-	for i := 0; i < N; i++ {
-		af.wHistory[i] = make([]float64, af.n)
+	p, err := NewFiltNLMS(s.N, s.Mu, s.Eps, s.W)
+	if err != nil {
+		return err
 	}
+	*af = *p.(*FiltNLMS)
+	return nil
+}
 
-	y = make([]float64, N)
-	e = make([]float64, N)
-	w := af.w.RawRowView(0)
-	//adaptation loop
-	for i := 0; i < N; i++ {
-		copy(af.wHistory[i], w)
-		y[i] = floats.Dot(w, x[i])
-		e[i] = d[i] - y[i]
-		nu := af.mu / (af.eps + floats.Dot(x[i], x[i]))
-		for j := 0; j < af.n; j++ {
-			w[j] += nu * e[i] * x[i][j]
-		}
+//MarshalBinary implements encoding.BinaryMarshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltNLMS) MarshalBinary() ([]byte, error) {
+	return encodeState(af.state())
+}
+
+//UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (af *FiltNLMS) UnmarshalBinary(data []byte) error {
+	s, err := decodeState(data)
+	if err != nil {
+		return err
 	}
-	wHist = af.wHistory
-	return y, e, af.wHistory, nil
+	return af.setState(s)
 }
 
-func (af *FiltNLMS) clone() AdaptiveFilter {
-	altaf := *af
-	return &altaf
+//MarshalJSON implements json.Marshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltNLMS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(af.state())
+}
+
+//UnmarshalJSON implements json.Unmarshaler.
+func (af *FiltNLMS) UnmarshalJSON(data []byte) error {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
 }
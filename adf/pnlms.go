@@ -0,0 +1,258 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the proportionate NLMS filters.
+const (
+	kindPNLMS  = "PNLMS filter"
+	kindIPNLMS = "IPNLMS filter"
+	kindMPNLMS = "MPNLMS filter"
+)
+
+//proportionateBase is base struct of the proportionate NLMS filters.
+//Each tap is adapted with its own gain `g`, which is large for the taps of large magnitude,
+//so that the active taps of a sparse system converge fast:
+//w += mu * e * g∘x / (x·(g∘x) + eps).
+type proportionateBase struct {
+	filtBase
+	eps float64
+	//g is the gains of the taps.
+	g []float64
+	//gx is the work buffer of g∘x.
+	gx []float64
+}
+
+//initProportionate initializes the common part of the proportionate NLMS filters.
+//`mu` and `eps` are checked in the same way as NLMS.
+func (af *proportionateBase) initProportionate(kind string, n int, mu, eps float64, w []float64) error {
+	var err error
+	af.filtBase, af.eps, err = newNLMSBase(kind, n, mu, eps, w)
+	if err != nil {
+		return err
+	}
+	af.g = make([]float64, n)
+	af.gx = make([]float64, n)
+	return nil
+}
+
+//step calculates the estimated value `y` and the error `e`,
+//and update filter weights with the gains which are already calculated.
+func (af *proportionateBase) step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.MulTo(af.gx, af.g, x)
+	nu := af.mu / (af.eps + floats.Dot(x, af.gx))
+	floats.AddScaled(w, nu*e, af.gx)
+	return y, e
+}
+
+//clone returns a deep copy of the base.
+func (af *proportionateBase) clone() proportionateBase {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.g = append([]float64{}, af.g...)
+	altaf.gx = make([]float64, len(af.gx))
+	return altaf
+}
+
+//proportionateGains calculates the gains of PNLMS from the magnitudes `f` of the taps into `g`.
+//Small taps get at least `rho` times the largest magnitude, and all taps get at least `deltaP`,
+//so that taps of zero magnitude still adapt. The gains are normalized to the mean of 1.
+func proportionateGains(g, f []float64, rho, deltaP float64) {
+	fMax := math.Max(deltaP, floats.Max(f))
+	for i, v := range f {
+		g[i] = math.Max(rho*fMax, v)
+	}
+	floats.Scale(float64(len(g))/floats.Sum(g), g)
+}
+
+//FiltPNLMS is base struct for PNLMS filter (Proportionate NLMS filter of Duttweiler).
+//Use NewFiltPNLMS to make instance.
+type FiltPNLMS struct {
+	proportionateBase
+	rho    float64
+	deltaP float64
+	f      []float64
+}
+
+//NewFiltPNLMS is constructor of PNLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//the proportionate parameter `rho`, the minimum magnitude `deltaP` and filter weight `w`.
+//Typical values are `rho` = 0.01 and `deltaP` = 0.01.
+func NewFiltPNLMS(n int, mu, eps, rho, deltaP float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltPNLMS)
+	if err = p.initProportionate(kindPNLMS, n, mu, eps, w); err != nil {
+		return nil, err
+	}
+	p.rho, err = p.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return nil, err
+	}
+	p.deltaP, err = p.checkFloatParam(deltaP, 0, 1, "deltaP")
+	if err != nil {
+		return nil, err
+	}
+	p.f = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltPNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltPNLMS) Step(d float64, x []float64) (y, e float64) {
+	for i, v := range af.w.RawRowView(0) {
+		af.f[i] = math.Abs(v)
+	}
+	proportionateGains(af.g, af.f, af.rho, af.deltaP)
+	return af.step(d, x)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltPNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltPNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.proportionateBase = af.clone()
+	altaf.f = make([]float64, len(af.f))
+	return &altaf
+}
+
+//FiltIPNLMS is base struct for IPNLMS filter (Improved Proportionate NLMS filter of Benesty and Gay).
+//The gains mix the uniform gain of NLMS and the proportionate gain by `alpha`:
+//g_l = (1-alpha)/2 + L*(1+alpha)*|w_l|/(2*||w||_1 + epsG).
+//Use NewFiltIPNLMS to make instance.
+type FiltIPNLMS struct {
+	proportionateBase
+	alpha float64
+}
+
+//epsIPNLMS is the small value to avoid division by zero in the gains of IPNLMS.
+const epsIPNLMS = 1e-6
+
+//NewFiltIPNLMS is constructor of IPNLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//the proportionality `alpha` in the range [-1, 1] and filter weight `w`.
+//`alpha` = -1 is the same as NLMS and `alpha` close to 1 is fully proportionate. Typical value is -0.5 or 0.
+func NewFiltIPNLMS(n int, mu, eps, alpha float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltIPNLMS)
+	if err = p.initProportionate(kindIPNLMS, n, mu, eps, w); err != nil {
+		return nil, err
+	}
+	p.alpha, err = p.checkFloatParam(alpha, -1, 1, "alpha")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltIPNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltIPNLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	L := float64(len(w))
+	norm := 2*floats.Norm(w, 1) + epsIPNLMS
+	for i, v := range w {
+		af.g[i] = (1-af.alpha)/2 + L*(1+af.alpha)*math.Abs(v)/norm
+	}
+	return af.step(d, x)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltIPNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltIPNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.proportionateBase = af.clone()
+	return &altaf
+}
+
+//FiltMPNLMS is base struct for MPNLMS filter (mu-law Proportionate NLMS filter of Deng and Doroslovački).
+//It is PNLMS whose gains are calculated from the mu-law compressed magnitudes ln(1 + beta*|w_l|).
+//Use NewFiltMPNLMS to make instance.
+type FiltMPNLMS struct {
+	FiltPNLMS
+	beta float64
+}
+
+//NewFiltMPNLMS is constructor of MPNLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//the proportionate parameter `rho`, the minimum magnitude `deltaP`, the mu-law parameter `beta` and filter weight `w`.
+//`beta` is the inverse of the precision of the weights, typically 1000.
+func NewFiltMPNLMS(n int, mu, eps, rho, deltaP, beta float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltMPNLMS)
+	if err = p.initProportionate(kindMPNLMS, n, mu, eps, w); err != nil {
+		return nil, err
+	}
+	p.rho, err = p.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return nil, err
+	}
+	p.deltaP, err = p.checkFloatParam(deltaP, 0, 1, "deltaP")
+	if err != nil {
+		return nil, err
+	}
+	p.beta, err = p.checkFloatParam(beta, 0, math.MaxFloat64, "beta")
+	if err != nil {
+		return nil, err
+	}
+	p.f = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltMPNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltMPNLMS) Step(d float64, x []float64) (y, e float64) {
+	for i, v := range af.w.RawRowView(0) {
+		af.f[i] = math.Log1p(af.beta * math.Abs(v))
+	}
+	proportionateGains(af.g, af.f, af.rho, af.deltaP)
+	return af.step(d, x)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltMPNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltMPNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.proportionateBase = af.clone()
+	altaf.f = make([]float64, len(af.f))
+	return &altaf
+}
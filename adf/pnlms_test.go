@@ -0,0 +1,103 @@
+package adf
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//misalignment returns the normalized misalignment ||w-h||^2 / ||h||^2 in dB.
+func misalignment(w, h []float64) float64 {
+	diff := make([]float64, len(h))
+	floats.SubTo(diff, w, h)
+	return 10 * math.Log10(floats.Dot(diff, diff)/floats.Dot(h, h))
+}
+
+//newSparseSystem returns a sparse impulse response of length `n` with a few active taps.
+func newSparseSystem(n int) []float64 {
+	h := make([]float64, n)
+	h[5] = 1
+	h[12] = -0.5
+	h[30] = 0.25
+	return h
+}
+
+//convergenceTime returns the first sample where the misalignment of `af` drops below `level` dB.
+func convergenceTime(af AdaptiveFilter, d []float64, x [][]float64, h []float64, level float64) int {
+	n := len(d)
+	_, _, _, err := af.Run(d, x, WithHistoryFunc(func(i int, w []float64) error {
+		if n == len(d) && misalignment(w, h) < level {
+			n = i
+		}
+		return nil
+	}))
+	check(err)
+	return n
+}
+
+func TestProportionateNLMS_sparse(t *testing.T) {
+	rand.Seed(1)
+	L := 64
+	h := newSparseSystem(L)
+	d, x := newSystemData(2000, h, 0.001)
+
+	//convergence time of NLMS in the same scenario
+	ref := convergenceTime(Must(NewFiltNLMS(L, 0.5, 1e-3, nil)), d, x, h, -30)
+
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "PNLMS", af: Must(NewFiltPNLMS(L, 0.5, 1e-3, 0.01, 0.01, nil))},
+		{name: "IPNLMS", af: Must(NewFiltIPNLMS(L, 0.5, 1e-3, 0, nil))},
+		{name: "MPNLMS", af: Must(NewFiltMPNLMS(L, 0.5, 1e-3, 0.01, 0.01, 1000, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convergenceTime(tt.af, d, x, h, -30)
+			if got >= ref {
+				t.Errorf("%s reaches -30 dB misalignment at sample %d, want faster than NLMS at %d", tt.name, got, ref)
+			}
+		})
+	}
+}
+
+func TestFiltIPNLMS_NLMS(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(256, newSparseSystem(32), 0.01)
+	//IPNLMS with alpha = -1 is NLMS
+	_, _, wantW, err := Must(NewFiltNLMS(32, 0.5, 1e-3, nil)).Run(d, x)
+	check(err)
+	_, _, wHist, err := Must(NewFiltIPNLMS(32, 0.5, 1e-3, -1, nil)).Run(d, x)
+	check(err)
+	for i := range wantW {
+		if !floats.EqualApprox(wHist[i], wantW[i], 1e-12) {
+			t.Fatalf("Run() wHist[%d] = %v, want %v", i, wHist[i], wantW[i])
+		}
+	}
+}
+
+func TestProportionateNLMS_invalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "PNLMS eps", err: second(NewFiltPNLMS(4, 0.5, 2, 0.01, 0.01, nil))},
+		{name: "PNLMS rho", err: second(NewFiltPNLMS(4, 0.5, 1e-3, 2, 0.01, nil))},
+		{name: "IPNLMS mu", err: second(NewFiltIPNLMS(4, 3, 1e-3, 0, nil))},
+		{name: "IPNLMS alpha", err: second(NewFiltIPNLMS(4, 0.5, 1e-3, 1.5, nil))},
+		{name: "MPNLMS beta", err: second(NewFiltMPNLMS(4, 0.5, 1e-3, 0.01, 0.01, -1, nil))},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: error = nil, want error", tt.name)
+		}
+	}
+}
+
+//second returns the error of a constructor.
+func second(_ AdaptiveFilter, err error) error {
+	return err
+}
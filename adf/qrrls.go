@@ -0,0 +1,142 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindIQRRLS is the kind name of FiltIQRRLS.
+const kindIQRRLS = "inverse QR-RLS filter"
+
+//FiltIQRRLS is base struct for inverse QR-RLS filter.
+//It gives the same result as FiltRLS in exact arithmetic,
+//but propagates the lower triangular square root `sMat` of the inverse correlation matrix P = S S^T
+//with Givens rotations instead of P itself.
+//P stays symmetric and positive definite by construction, so the filter does not diverge in long runs.
+//Use NewFiltIQRRLS to make instance.
+type FiltIQRRLS struct {
+	filtBase
+	eps  float64
+	sMat *mat.Dense
+	//a and g are the workspaces for S^T x and the rotated gain vector.
+	a []float64
+	g []float64
+}
+
+//NewFiltIQRRLS is constructor of inverse QR-RLS filter.
+//The parameters are the same as NewFiltRLS.
+//This func initialize filter length `n`, forgetting factor `mu`, small enough value `eps`, and filter weight `w`.
+func NewFiltIQRRLS(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltIQRRLS)
+	p.kind = kindIQRRLS
+	p.n = n
+	p.muMin = math.SmallestNonzeroFloat64
+	p.muMax = 1
+	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, math.SmallestNonzeroFloat64, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.initSMat()
+	p.a = make([]float64, n)
+	p.g = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltIQRRLS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltIQRRLS) Step(d float64, x []float64) (y, e float64) {
+	y = floats.Dot(af.w.RawRowView(0), x)
+	e = d - y
+	af.update(e, x)
+	return y, e
+}
+
+//update updates the square root `sMat` and the filter weights with the error `e` and input `x`.
+//
+//The Givens rotations annihilate the first row of the pre-array and give the post-array
+//	[ 1  mu^(-1/2) x^T S ]      [ gamma^(-1/2)  0^T ]
+//	[ 0  mu^(-1/2) S     ]  ->  [ g             S'  ]
+//where gamma is the conversion factor and g gamma^(1/2) is the gain vector.
+//The weights are updated by w = w + e g / gamma^(-1/2).
+//The rotations are applied from the last column, so S stays lower triangular.
+func (af *FiltIQRRLS) update(e float64, x []float64) {
+	n := af.n
+	lam := 1 / math.Sqrt(af.mu)
+	a, g := af.a, af.g
+	for j := 0; j < n; j++ {
+		var s float64
+		for i := j; i < n; i++ {
+			s += af.sMat.At(i, j) * x[i]
+		}
+		a[j] = lam * s
+		g[j] = 0
+	}
+	top := 1.0
+	for j := n - 1; j >= 0; j-- {
+		if a[j] == 0 {
+			for i := j; i < n; i++ {
+				af.sMat.Set(i, j, lam*af.sMat.At(i, j))
+			}
+			continue
+		}
+		r := math.Hypot(top, a[j])
+		c, s := top/r, a[j]/r
+		for i := j; i < n; i++ {
+			sij := lam * af.sMat.At(i, j)
+			g[i], sij = c*g[i]+s*sij, -s*g[i]+c*sij
+			af.sMat.Set(i, j, sij)
+		}
+		top = r
+	}
+	floats.AddScaled(af.w.RawRowView(0), e/top, g)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+func (af *FiltIQRRLS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Reset sets the filter weights to zeros and
+//initializes the square root `sMat` to the identity matrix divided by the square root of `eps`.
+func (af *FiltIQRRLS) Reset() {
+	af.filtBase.Reset()
+	af.initSMat()
+}
+
+//initSMat initializes the square root `sMat` to the identity matrix divided by the square root of `eps`,
+//that is the inverse correlation matrix is the identity matrix divided by `eps` as FiltRLS.
+func (af *FiltIQRRLS) initSMat() {
+	var Ss = make([]float64, af.n*af.n)
+	for i := 0; i < af.n; i++ {
+		Ss[i*(af.n+1)] = 1 / math.Sqrt(af.eps)
+	}
+	af.sMat = mat.NewDense(af.n, af.n, Ss)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltIQRRLS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.sMat = mat.DenseCopyOf(af.sMat)
+	altaf.a = make([]float64, len(af.a))
+	altaf.g = make([]float64, len(af.g))
+	return &altaf
+}
@@ -0,0 +1,112 @@
+package adf
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//newIllConditionedData makes the input of two sinusoids with weak white noise,
+//whose correlation matrix has a large eigenvalue spread,
+//and the desired values of the system `h` with noise.
+func newIllConditionedData(n int, h []float64, noise float64) (d []float64, x [][]float64) {
+	L := len(h)
+	x = make([][]float64, n)
+	d = make([]float64, n)
+	for i := range x {
+		x[i] = make([]float64, L)
+		for j := range x[i] {
+			k := float64(i + j)
+			x[i][j] = math.Sin(0.3*k) + 0.5*math.Sin(1.1*k) + 1e-3*rand.NormFloat64()
+		}
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*noise
+	}
+	return d, x
+}
+
+func TestFiltIQRRLS_agreesWithRLS(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	af := Must(NewFiltIQRRLS(4, 0.99, 0.1, nil))
+	want := Must(NewFiltRLS(4, 0.99, 0.1, nil))
+	y, e, wHist, err := af.Run(d, x)
+	check(err)
+	wantY, wantE, wantWHist, err := want.Run(d, x)
+	check(err)
+	for i := range d {
+		if !floats.EqualWithinAbs(y[i], wantY[i], 1e-12) || !floats.EqualWithinAbs(e[i], wantE[i], 1e-12) {
+			t.Fatalf("Run() y[%d], e[%d] = %v, %v, want %v, %v", i, i, y[i], e[i], wantY[i], wantE[i])
+		}
+		if !floats.EqualApprox(wHist[i], wantWHist[i], 1e-12) {
+			t.Fatalf("Run() wHist[%d] = %v, want %v", i, wHist[i], wantWHist[i])
+		}
+	}
+}
+
+func TestFiltIQRRLS_longRun(t *testing.T) {
+	rand.Seed(1)
+	L := 8
+	h := make([]float64, L)
+	for i := range h {
+		h[i] = rand.NormFloat64()
+	}
+	const noise = 1e-3
+	d, x := newIllConditionedData(50000, h, noise)
+	mse := func(e []float64) float64 {
+		last := e[len(e)-1000:]
+		return floats.Dot(last, last) / float64(len(last))
+	}
+
+	rls := Must(NewFiltRLS(L, 0.999, 0.01, nil))
+	_, e, _, err := rls.Run(d, x, WithoutHistory())
+	check(err)
+	//FiltRLS diverges in this scenario
+	if m := mse(e); !(m > 100*noise*noise) {
+		t.Fatalf("FiltRLS MSE = %v, want the divergence of FiltRLS in this scenario", m)
+	}
+	//and rMat is no longer a valid inverse correlation matrix
+	var chol mat.Cholesky
+	if chol.Factorize(mat.NewSymDense(L, rls.(*FiltRLS).rMat.RawMatrix().Data)) {
+		t.Errorf("FiltRLS rMat is positive definite, want the loss of positive definiteness")
+	}
+
+	iqr := Must(NewFiltIQRRLS(L, 0.999, 0.01, nil))
+	_, e, _, err = iqr.Run(d, x, WithoutHistory())
+	check(err)
+	if m := mse(e); m > 2*noise*noise {
+		t.Errorf("FiltIQRRLS MSE = %v, want about the noise power %v", m, noise*noise)
+	}
+	//the inverse correlation matrix S S^T stays positive definite
+	s := iqr.(*FiltIQRRLS).sMat
+	for i := 0; i < L; i++ {
+		if s.At(i, i) == 0 || math.IsNaN(s.At(i, i)) {
+			t.Errorf("FiltIQRRLS sMat[%d][%d] = %v, want nonzero", i, i, s.At(i, i))
+		}
+	}
+}
+
+func TestFiltIQRRLS_Reset(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	af := Must(NewFiltIQRRLS(4, 0.99, 0.1, nil))
+	_, want, _, err := af.Run(d, x)
+	check(err)
+	af.Reset()
+	_, e, _, err := af.Run(d, x)
+	check(err)
+	if !floats.Equal(e, want) {
+		t.Errorf("Run() after Reset() e = %v, want %v", e, want)
+	}
+}
+
+func TestNewFiltIQRRLS_invalidParams(t *testing.T) {
+	if _, err := NewFiltIQRRLS(4, 0, 0.1, nil); err == nil {
+		t.Errorf("NewFiltIQRRLS() with mu = 0: error = nil, want error")
+	}
+	if _, err := NewFiltIQRRLS(4, 0.99, 0, nil); err == nil {
+		t.Errorf("NewFiltIQRRLS() with eps = 0: error = nil, want error")
+	}
+}
@@ -1,19 +1,24 @@
 package adf
 
 import (
-	"errors"
+	"encoding/json"
+	"math"
 
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
+//kindRLS is the kind name of FiltRLS.
+const kindRLS = "RLS filter"
+
 //FiltRLS is base struct for RLS filter.
 //Use NewFiltRLS to make instance.
 type FiltRLS struct {
 	filtBase
-	wHist [][]float64
-	eps   float64
-	rMat  *mat.Dense
+	eps  float64
+	rMat *mat.Dense
+	//k is the workspace for the gain vector.
+	k []float64
 }
 
 //NewFiltRLS is constructor of RLS filter.
@@ -21,15 +26,15 @@ type FiltRLS struct {
 func NewFiltRLS(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, error) {
 	var err error
 	p := new(FiltRLS)
-	p.kind = "RLS filter"
+	p.kind = kindRLS
 	p.n = n
-	p.muMin = 0
+	p.muMin = math.SmallestNonzeroFloat64
 	p.muMax = 1
 	p.mu, err = p.checkFloatParam(mu, p.muMin, p.muMax, "mu")
 	if err != nil {
 		return nil, err
 	}
-	p.eps, err = p.checkFloatParam(mu, 0, 1, "eps")
+	p.eps, err = p.checkFloatParam(eps, math.SmallestNonzeroFloat64, 1, "eps")
 	if err != nil {
 		return nil, err
 	}
@@ -37,101 +42,136 @@ func NewFiltRLS(n int, mu float64, eps float64, w []float64) (AdaptiveFilter, er
 	if err != nil {
 		return nil, err
 	}
-	var Rs = make([]float64, n*n)
-	for i := 0; i < n; i++ {
-		Rs[i*(n+1)] = 1 / eps
-	}
-	p.rMat = mat.NewDense(n, n, Rs)
+	p.initRMat()
+	p.k = make([]float64, n)
 	return p, nil
 }
 
 //Adapt calculates the error `e` between desired value `d` and estimated value `y`,
 //and update filter weights according to error `e`.
 func (af *FiltRLS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e`.
+func (af *FiltRLS) Step(d float64, x []float64) (y, e float64) {
+	y = floats.Dot(af.w.RawRowView(0), x)
+	e = d - y
+	af.update(e, x)
+	return y, e
+}
+
+//update updates the inverse correlation matrix `rMat` and the filter weights with the error `e` and input `x`.
+//
+//With k = R x, the inverse correlation matrix is updated by the rank-one downdate
+//	R = (R - k k^T / (mu + x^T k)) / mu
+//and the weights by
+//	w = w + e k / (mu + x^T k),
+//which is the same as w + e R x with the updated R.
+//The update works in place on the preallocated workspace and does not allocate.
+func (af *FiltRLS) update(e float64, x []float64) {
 	w := af.w.RawRowView(0)
-	R1 := mat.NewDense(af.n, af.n, nil)
-	var R2 float64
-	xVec := mat.NewDense(1, af.n, nil)
-	aux1 := mat.NewDense(af.n, 1, nil)
-	aux4 := mat.NewDense(1, af.n, nil)
-	var aux2 float64
-	aux3 := mat.NewDense(af.n, af.n, nil)
-	dwT := mat.NewDense(af.n, 1, nil)
-
-	y := floats.Dot(w, x)
-	e := d - y
-
-	xVec.SetRow(0, x)
-	aux1.Mul(af.rMat, xVec.T())
-	aux2 = floats.Dot(mat.Col(nil, 0, aux1), mat.Row(nil, 0, xVec))
-	R1 = mat.DenseCopyOf(af.rMat)
-	R1.Scale(aux2, R1)
-	aux4.Mul(xVec, af.rMat)
-
-	R2 = af.mu + mat.Dot(aux4.RowView(0), mat.DenseCopyOf(xVec.T()).ColView(0))
-	R1.Scale(1/R2, R1)
-	aux3.Sub(af.rMat, R1)
-	af.rMat.Scale(1/af.mu, aux3)
-	dwT.Mul(af.rMat, xVec.T())
-	dwT.Scale(e, dwT)
-
-	floats.Add(w, mat.Col(nil, 0, dwT))
+	k := af.k
+	for i := 0; i < af.n; i++ {
+		k[i] = floats.Dot(af.rMat.RawRowView(i), x)
+	}
+	den := af.mu + floats.Dot(x, k)
+	for i := 0; i < af.n; i++ {
+		r := af.rMat.RawRowView(i)
+		floats.AddScaled(r, -k[i]/den, k)
+		floats.Scale(1/af.mu, r)
+	}
+	floats.AddScaled(w, e/den, k)
 }
 
 //Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 //while updating filter weights according to error `e`.
-func (af *FiltRLS) Run(d []float64, x [][]float64) (y []float64, e []float64, wHist [][]float64, err error) {
-	//measure the data and check if the dimension agree
-	N := len(x)
-	if len(d) != N {
-		return nil, nil, nil, errors.New("the length of slice d and x must agree")
-	}
-	af.n = len(x[0])
-	af.wHist = make([][]float64, N)
-	for i := 0; i < N; i++ {
-		af.wHist[i] = make([]float64, af.n)
-	}
+func (af *FiltRLS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
 
-	y = make([]float64, N)
-	e = make([]float64, N)
-	w := af.w.RawRowView(0)
-	R1 := mat.NewDense(af.n, af.n, nil)
-	var R2 float64
-	xVec := mat.NewDense(1, af.n, nil)
-	aux1 := mat.NewDense(af.n, 1, nil)
-	aux4 := mat.NewDense(1, af.n, nil)
-	//aux2 := mat.NewDense(af.n, af.n, nil)
-	var aux2 float64
-	aux3 := mat.NewDense(af.n, af.n, nil)
-	dwT := mat.NewDense(af.n, 1, nil)
-	//adaptation loop
-	for i := 0; i < N; i++ {
-		copy(af.wHist[i], w)
-		y[i] = floats.Dot(w, x[i])
-		e[i] = d[i] - y[i]
-		xVec.SetRow(0, x[i])
-		aux1.Mul(af.rMat, xVec.T())
-		aux2 = floats.Dot(mat.Col(nil, 0, aux1), mat.Row(nil, 0, xVec))
-		R1 = mat.DenseCopyOf(af.rMat)
-		R1.Scale(aux2, R1)
-		//R1.Product(aux1.T(), xVec.T())
-		aux4.Mul(xVec, af.rMat)
-		R2 = af.mu + mat.Dot(aux4.RowView(0), mat.DenseCopyOf(xVec.T()).ColView(0))
-		//for j:=0;j<af.n;j++{
-		//	floats.AddConst(af.rMat.RawRowView(j))
-		//}
-		R1.Scale(1/R2, R1)
-		aux3.Sub(af.rMat, R1)
-		af.rMat.Scale(1/af.mu, aux3)
-		dwT.Mul(af.rMat, xVec.T())
-		dwT.Scale(e[i], dwT)
-		floats.Add(w, mat.Col(nil, 0, dwT))
+//Reset sets the filter weights to zeros and
+//initializes the inverse correlation matrix `rMat` to the identity matrix divided by `eps`.
+func (af *FiltRLS) Reset() {
+	af.filtBase.Reset()
+	af.initRMat()
+}
+
+//initRMat initializes the inverse correlation matrix `rMat` to the identity matrix divided by `eps`.
+func (af *FiltRLS) initRMat() {
+	var Rs = make([]float64, af.n*af.n)
+	for i := 0; i < af.n; i++ {
+		Rs[i*(af.n+1)] = 1 / af.eps
 	}
-	wHist = af.wHist
-	return y, e, af.wHist, nil
+	af.rMat = mat.NewDense(af.n, af.n, Rs)
 }
 
-func (af *FiltRLS) clone() AdaptiveFilter {
+//Clone returns a deep copy of the filter.
+func (af *FiltRLS) Clone() AdaptiveFilter {
 	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.rMat = mat.DenseCopyOf(af.rMat)
+	altaf.k = make([]float64, len(af.k))
 	return &altaf
 }
+
+//state returns the complete state of the filter.
+func (af *FiltRLS) state() *filterState {
+	return &filterState{
+		Version: stateVersion,
+		Kind:    kindRLS,
+		N:       af.n,
+		Mu:      af.mu,
+		W:       denseData(af.w),
+		Eps:     af.eps,
+		RMat:    denseData(af.rMat),
+	}
+}
+
+//setState restores the filter from the state `s`.
+func (af *FiltRLS) setState(s *filterState) error {
+	if err := checkState(s, kindRLS); err != nil {
+		return err
+	}
+	if err := checkStateLen(s.RMat, s.N*s.N, "rMat"); err != nil {
+		return err
+	}
+	p, err := NewFiltRLS(s.N, s.Mu, s.Eps, s.W)
+	if err != nil {
+		return err
+	}
+	*af = *p.(*FiltRLS)
+	af.rMat = mat.NewDense(s.N, s.N, s.RMat)
+	return nil
+}
+
+//MarshalBinary implements encoding.BinaryMarshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltRLS) MarshalBinary() ([]byte, error) {
+	return encodeState(af.state())
+}
+
+//UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (af *FiltRLS) UnmarshalBinary(data []byte) error {
+	s, err := decodeState(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
+}
+
+//MarshalJSON implements json.Marshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltRLS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(af.state())
+}
+
+//UnmarshalJSON implements json.Unmarshaler.
+func (af *FiltRLS) UnmarshalJSON(data []byte) error {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
+}
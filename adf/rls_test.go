@@ -10,6 +10,7 @@ import (
 
 	"github.com/tetsuzawa/go-adflib/misc"
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
 )
 
 func TestFiltRLS_Run(t *testing.T) {
@@ -54,9 +55,9 @@ func TestFiltRLS_Run(t *testing.T) {
 				d: d,
 				x: x,
 			},
-			want:    []float64{0, 0, 0, 0, -0.07083370077583785, 0.1580894627354706, -0.08719645102038076, -0.10720777411085475, 0.4104774254088424, 0.5239370815669278, 0.27174915148294443, 0.4761497035929073, 0.4187449457788772, -0.17929514481692901, 0.580669233442545, 0.15122671660942283, -0.5724000576851315, 0.015545696475589427, -0.3075318664240403, -0.014040768068631054, 0.2328087739761573, 0.13250283343697683, -0.39487165472107566, 0.1487407596029266, -0.44021870064624535, 0.5305365084925896, 0.5300196997369107, 0.15979754111642772, 0.2138938650214794, -0.07891954153327524, -0.8078218482793795, 0.6530965739304572, -0.4655542767113635, -0.35749197008312317, 0.8782255815716905, -0.20808832721285442, -0.14634305774193238, 0.8753548023972704, -0.3134940842519238, -0.4311926919617277, -0.47047906739419487, -0.9196125022971965, 1.080540803140396, -0.0692107699216383, 0.7287807797731012, -0.6597497785040464, -0.7675208689947604, 0.5482850237090681, -1.0178353109683378, 0.18639419471514093, 0.2780060790358463, 1.5018054953843198, -0.6767569515297152, -0.24460307555630154, 0.049411610995482885, 1.4662765617079996, 0.10045520594378321, 0.10782175570291667, -0.2915650162346456, -0.21273544587545062, 1.7542603847300464, 0.0029209011865537343, 0.48065600575978074, 0.35941104126864193,},
-			want1:   []float64{0, 0, 0, -1.233758177597947, -0.45016087037731245, 0.1647157898761093, 0.24600419119681638, -0.6240752420666242, 1.1749265368717807, 0.7749037659505065, 0.46069277432156874, 0.2239711988470775, 0.5808811752323852, -0.13724209807715923, 0.5200599603074758, 0.8384837035991088, -0.8626468644470967, 0.12180467709776135, -0.5385625070315568, 0.1701672633514457, 0.0469349446870142, 0.5702474281252043, -0.6746737476319448, 0.18154862600973515, -0.6804953917103879, 0.39676196227149363, 0.4430146821062746, 0.3503185693695884, 0.037646179037669814, 0.2456102604515728, -0.8308192295770549, 0.50113423977958, -0.3050066711384303, -0.4187212397906439, 0.5420539152171896, -0.12236843526211516, -0.10069165908830366, 0.8602784542572912, 0.10766015699506393, -0.6204726056422163, -0.2070444788689444, -1.0420254087877119, 0.9053784788407362, 0.028072661852645814, 0.474950834713316, -0.17884298401530374, -0.6355718593789048, 0.4259981401795535, -0.9751270590231447, 0.19483954558480956, 0.1315651155236034, 1.0284329479680867, -0.593485766965778, -0.17602473706502195, -0.04725094239284525, 0.9165837837239412, 0.09081091185682444, -0.09781555771807775, -0.16674783197159365, -0.2637221330907384, 0.9768614862943998, 0.04954973746071585, 0.31922039639378486, 0.3659264466750694,},
-			want2:   [][]float64{{0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0.21328975205753262, 0.09006854416229718, -0.05580595415095195, -0.027454378128618274,}, {0.24234391341788553, 0.07206675217320302, -0.06466214184240264, 0.013326881271223685,}, {0.24707027051881877, 0.07439193766629414, -0.07536923117363223, 0.036539593004849144,}, {0.24951039437255879, 0.0631555764560212, -0.05100907075653374, 0.056496633705246005,}, {0.27095445714807503, 0.01666536495157836, -0.08909613698590854, 0.03501858719122701,}, {0.34122167559056904, 0.07423172388968421, -0.056633333800459644, 0.06604887950684482,}, {0.37452428326165793, 0.09301172002512073, -0.03868205687468945, 0.09167954595026652,}, {0.3849554731953185, 0.1029826048865459, -0.02444572009008566, 0.0871715297030006,}, {0.3894166193703937, 0.1093521879552887, -0.02646268445364694, 0.09418533805435525,}, {0.40452232345701244, 0.10456888165401369, -0.009829176054057084, 0.10914120247693444,}, {0.40553754871491315, 0.10103852905036248, -0.0130034597731436, 0.11374380742888571,}, {0.41770168102104965, 0.11197579551384525, -0.028862129394560686, 0.11526166316118579,}, {0.43402659444281766, 0.08830521846258292, -0.026596584937061688, 0.1013056441971767,}, {0.45709999773398424, 0.08609683035152274, -0.012992655755344616, 0.09879536419549961,}, {0.45740702747619727, 0.08420549121198094, -0.01264365431943875, 0.09942069661791926,}, {0.46557295794101833, 0.08269866390737579, -0.015343551430495253, 0.09263822654118999,}, {0.46603466341809313, 0.08352593673413138, -0.013265341423979362, 0.08947531066741704,}, {0.4662558241475206, 0.08408151939813244, -0.014110906223327068, 0.08973643196012497,}, {0.47268101600746165, 0.07430274763679608, -0.011091095647738079, 0.07948982878202866,}, {0.4836576256365425, 0.07091302931111951, 0.0004106517961241594, 0.06997308147908303,}, {0.48452778508312006, 0.06796046711307073, 0.002853653476712666, 0.07253657608740971,}, {0.4950857154506997, 0.05922465059121084, -0.006313028122836738, 0.06773091654353197,}, {0.5000332207370793, 0.06441617511085292, -0.003591356263270937, 0.06907298248772939,}, {0.5057305001361205, 0.06740299043991227, -0.002118547143887663, 0.07004898458655105,}, {0.5080012288149465, 0.06852269471934398, -0.0013765407885477634, 0.06275474468057012,}, {0.5081155852914007, 0.0685984765430469, -0.00212150853528442, 0.06327948724935059,}, {0.5085837708896076, 0.06399601182118815, 0.0011203890790070425, 0.06111520635437877,}, {0.5232764070342247, 0.05364676963391723, 0.008029511392002184, 0.06807500885013908,}, {0.5292269488671951, 0.04967420617384538, 0.0040278081609026895, 0.07539714274204838,}, {0.5315648662569127, 0.05202927278675409, -0.00028138521707425385, 0.0763997637724754,}, {0.5347106597061774, 0.046273243540807585, 0.0010578714529172763, 0.07740093224533853,}, {0.541805053802106, 0.04462258920835925, -0.0001760840048163595, 0.08607054013346661,}, {0.5421669336655998, 0.04489311443209869, -0.0020767584570179605, 0.0862959467886073,}, {0.542381432981555, 0.04338607060004754, -0.0018980336317647101, 0.08720910381303029,}, {0.5547638561997946, 0.0419176020798721, -0.009400865666666067, 0.08237548861351324,}, {0.5545880379568494, 0.041019295628377875, -0.009979589500222315, 0.08069990609211786,}, {0.5594074232990128, 0.044124130460591576, -0.000990142936896709, 0.07159918716556841,}, {0.5603839498269007, 0.04695147346416715, -0.0038524831046954674, 0.07165848023967344,}, {0.5737533625621668, 0.033416572660254246, -0.003572109064810521, 0.06345452746856461,}, {0.5850623182835984, 0.0331823088375315, 0.003282624484648859, 0.05867910255291364,}, {0.5850552381570477, 0.033389478602103465, 0.0031382974078386265, 0.058437621489600515,}, {0.5884546015479309, 0.0310212750071649, -0.0008240659607457752, 0.061189017631247886,}, {0.5893078286925333, 0.032448853273695656, -0.001815351456057537, 0.06321675942470102,}, {0.5941859159586684, 0.029061594399591353, 0.005113516581203671, 0.06189133636721442,}, {0.596393063915402, 0.02454672337870958, 0.005977166187528134, 0.06281918184244649,}, {0.6061720227512739, 0.022676106497340857, 0.003967504634323372, 0.050403946133510624,}, {0.6065307553530287, 0.02306150402304923, 0.006348403604938544, 0.04920867554988519,}, {0.6067808948104553, 0.02460680916117229, 0.005572621937123176, 0.048951783428990855,}, {0.6184178506499706, 0.01876476749137102, 0.003638089919713424, 0.04896172067628025,}, {0.6216802034947186, 0.0198450620847623, 0.0036325406941522483, 0.042841842078775186,}, {0.6219926472594248, 0.01984345713280184, 0.0018625439541457552, 0.04269976895827957,}, {0.6219922265813169, 0.019379518711055133, 0.0018253048012108614, 0.042697820770376045,}, {0.6307773312165379, 0.02008467663660123, 0.0018621955474377924, 0.041008117748175595,}, {0.6308470621493262, 0.020088324650829627, 0.0016951059301978388, 0.040834413007380915,}, {0.6308432534844939, 0.020262772531015057, 0.0018764602495764887, 0.03979486453564818,}, {0.6311317696553094, 0.0205627111357855, 0.00015716997145658767, 0.039761833326067204,}, {0.6315916805004157, 0.017926437471051885, 0.00010652156784446182, 0.03898973556052577,}, {0.6410578532802742, 0.018108302709866096, 0.0028789237464689763, 0.04150378301385607,}, {0.641066998062177, 0.0182477082028335, 0.003005338314772641, 0.0413052082650263,}, {0.6419542021132445, 0.0190522354472455, 0.0017415694499211098, 0.04017925700634413,},},
+			want:    []float64{0, 0, 0, 0, -0.5209896714367653, 0.32280940358343546, 0.15880223659963263, -0.7312757446168006, 1.585395125202713, 1.2988384412603657, 0.7324403553050144, 0.7001208688563435, 0.9996244604623253, -0.31653699277090797, 1.1007279434500294, 0.9897076895826061, -1.4350453028519063, 0.13735023114510345, -0.8460934615937682, 0.15612625630634847, 0.2797435521606163, 0.7027494579719928, -1.0695445963509411, 0.33028927390540136, -1.1207131770591434, 0.9272979375310698, 0.973033849909601, 0.5101157867026297, 0.2515398017370967, 0.16669062725080377, -1.6386402349583271, 1.1542302822581116, -0.7705605073432853, -0.7762129320666444, 1.420279017622551, -0.33045657651408805, -0.24703479802482606, 1.7356324649711043, -0.20583393723021504, -1.0516647495972058, -0.6775232294322802, -1.9616372456772482, 1.9859186873436712, -0.04113815706743953, 1.20373131025081, -0.8385925106007689, -1.403092402344591, 0.9742830096625003, -1.9929618581223867, 0.3812336564429523, 0.40957100230113447, 2.530237940227737, -1.2702424795478113, -0.42062774567640676, 0.0021605819669079777, 2.382859906392317, 0.19126609679675133, 0.010006159003315961, -0.45831277322823094, -0.4764575907674582, 2.7311213939836074, 0.05247062365203705, 0.7998762803627932, 0.7253374369146381,},
+			want1:   []float64{0, 0, 0, -1.233758177597947, -4.8997163849540115e-06, -4.150971855576557e-06, 5.5035768029843e-06, -7.2715606784790054e-06, 8.837077910106572e-06, 2.4062570684879603e-06, 1.5704994987819632e-06, 3.358364131234026e-08, 1.6605489371324111e-06, -2.501231802787274e-07, 1.2502999913177604e-06, 2.7306259254844534e-06, -1.6192803218295637e-06, 1.4242824733501358e-07, -9.118618289338798e-07, 2.3897646617143664e-07, 1.6650255518779744e-07, 8.035901883918228e-07, -8.060020793010381e-07, 1.1170726038622902e-07, -9.152974898007926e-07, 5.332330134111274e-07, 5.319335842912665e-07, 3.2378338643557214e-07, 2.4232205247942673e-07, 9.166749379008188e-08, -8.428981073116404e-07, 5.314519255783523e-07, -4.405065084922555e-07, -2.7780712263947294e-07, 4.791663290593107e-07, -1.8596088152689205e-07, 8.11945900236477e-08, 7.916834572352371e-07, 9.973355141745799e-09, -5.48006738210205e-07, -3.168308591039448e-07, -6.654076603052062e-07, 5.946374610488192e-07, 4.8998447042813975e-08, 3.042356071514263e-07, -2.5191858121775823e-07, -3.260290741824434e-07, 1.542261213183238e-07, -5.118690957761629e-07, 8.385699817869963e-08, 1.9225831521252346e-07, 5.031246694819913e-07, -2.389476818631664e-07, -6.694491672121217e-08, 8.663572966102415e-08, 4.3903962376745653e-07, 2.1003856320866987e-08, 3.8981522966149984e-08, -7.497800830202905e-08, 1.1801269184097407e-08, 4.770408388310443e-07, 1.4995232534842362e-08, 1.217907723560785e-07, 5.102907318921268e-08,},
+			want2:   [][]float64{{0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0, 0, 0, 0,}, {0.9999908028594403, 4.479019943689238e-06, -3.3551803397199297e-06, 1.1007591529966998e-06,}, {0.9999922915122009, 3.721029420591413e-06, -3.4221736567046096e-06, 2.2365318519065177e-06,}, {0.9999927952770786, 2.6755963633062667e-06, -2.2679177629915645e-06, 9.183364930501771e-07,}, {0.999993654725945, 1.616117645984269e-06, -7.038397199463555e-07, 1.3425583017262955e-06,}, {0.9999950351567227, -4.058927974004577e-07, -1.1828158591779809e-06, 6.109492845769808e-07,}, {0.9999976402835292, 5.722907692382563e-07, -4.869337078120967e-07, 5.805988599913231e-07,}, {0.9999980417028614, 6.255286212312265e-07, -3.6192079395730226e-07, 6.707607380589707e-07,}, {0.9999982231701153, 6.335942809429221e-07, -1.4494700251527663e-07, 4.825651814509549e-07,}, {0.999998223249979, 6.37879011752438e-07, -1.4892876065000202e-07, 4.86384181470142e-07,}, {0.9999984168005693, 4.41931772570009e-07, 2.7087176768112333e-08, 5.325777544432644e-07,}, {0.9999984207704755, 4.210207507943395e-07, 4.247990863255011e-09, 5.63605980594292e-07,}, {0.9999985202172236, 5.350611500661684e-07, -1.5786314141090717e-07, 5.632339476087442e-07,}, {0.9999989422028711, 1.6827782863360196e-07, -3.7476763922856557e-08, 2.9764266542569424e-07,}, {0.9999991416767988, 1.1137435595416724e-07, 5.7929260831264336e-08, 2.1681161866726493e-07,}, {0.9999991435670663, 1.0164500674335794e-07, 6.027679753434174e-08, 2.1986076248957517e-07,}, {0.9999992168142763, 8.95660551005445e-08, 4.5174999979600296e-08, 1.6222098466818583e-07,}, {0.9999992217839488, 9.339685226953206e-08, 5.771480328458867e-08, 1.4318485265191736e-07,}, {0.9999992241975573, 1.0217770836235482e-07, 4.4932750315437984e-08, 1.462923793663357e-07,}, {0.9999992760571838, 4.971032687593276e-08, 6.341368933265701e-08, 9.313311003431561e-08,}, {0.9999993287822513, 3.4887108622688815e-08, 1.084324792743852e-07, 4.9883170014672906e-08,}, {0.9999993297999745, 2.6752158167062754e-08, 1.1374899918656539e-07, 5.627337280547643e-08,}, {0.9999993974709251, -1.5456465774442107e-08, 6.170551728543057e-08, 3.59941515758292e-08,}, {0.9999994230165953, 1.071284723030429e-08, 7.112329654879931e-08, 3.681040625984688e-08,}, {0.9999994495630248, 2.3184073889934502e-08, 7.353957979431141e-08, 3.7278881150884246e-08,}, {0.9999994584742945, 2.986408094892374e-08, 7.603016261419688e-08, 1.169733958871509e-08,}, {0.9999994632690031, 3.1186688143300266e-08, 5.759061456151134e-08, 2.2799613391464166e-08,}, {0.9999994639549504, 2.468905192658587e-08, 6.174435207927026e-08, 2.1136471619820276e-08,}, {0.9999995202057638, -1.7745198293378634e-08, 7.83755213363058e-08, 4.893665256582679e-08,}, {0.9999995436109177, -3.616222627928307e-08, 5.999585173697984e-08, 7.398720190576509e-08,}, {0.9999995605148791, -2.3431794774376792e-08, 3.60275620863675e-08, 7.343819803974309e-08,}, {0.9999995674797555, -3.785701817932478e-08, 3.773948343416586e-08, 7.602191336446349e-08,}, {0.999999587018316, -4.324689415755189e-08, 3.417435454772977e-08, 9.964022700865224e-08,}, {0.9999995900989332, -4.2448516352924056e-08, 2.309174674708888e-08, 9.891575070533207e-08,}, {0.9999995906674525, -3.7705024978781603e-08, 2.2739992232710478e-08, 9.605332183683652e-08,}, {0.9999996422267093, -4.088056357154499e-08, -6.134944816354268e-09, 6.666086309815571e-08,}, {0.999999642235041, -4.1215226538996725e-08, -6.3996043830571365e-09, 6.610528159426222e-08,}, {0.9999996646374957, -2.6294346992620743e-08, 1.996014272549467e-08, 3.8999226436670396e-08,}, {0.999999672601019, -1.0298299576143532e-08, 5.4512463438082556e-09, 3.6424442257536375e-08,}, {0.999999706958146, -3.92457793632703e-08, -6.460063220406377e-10, 1.6118516972398722e-08,}, {0.9999997371749976, -3.3165928758254025e-08, 1.4261731434457037e-08, 5.650309855015447e-09,}, {0.9999997373907226, -3.178567332037694e-08, 1.3239060545113107e-08, 3.896070983145655e-09,}, {0.9999997456050402, -3.803366428315224e-08, 3.1240913500261643e-09, 8.943319035447818e-09,}, {0.99999975103337, -3.022932183284146e-08, 3.7335896534369177e-10, 1.8103895380803154e-08,}, {0.9999997603223615, -3.375078613336973e-08, 1.2644061013762712e-08, 1.7587607116670502e-08,}, {0.9999997624705181, -3.983998214525788e-08, 1.2852118681472722e-08, 1.913110786085047e-08,}, {0.9999997832099777, -3.994394945681129e-08, 3.3990697927573127e-09, -5.15657895260612e-09,}, {0.9999997838121578, -3.8419597378390744e-08, 7.222873227573571e-09, -6.351320262598428e-09,}, {0.9999997869603633, -2.96256695741388e-08, 4.034295609487875e-09, -8.536552000030723e-09,}, {0.9999998088384219, -3.7811724929048493e-08, -1.9697940028596694e-09, -8.298567595497176e-09,}, {0.9999998138578825, -3.519385459085097e-08, -3.3449062135505107e-09, -1.7280365342197797e-08,}, {0.9999998142602811, -3.5625224897749715e-08, -6.0934486924706045e-09, -1.7889082443723168e-08,}, {0.9999998149355164, -3.204646095679814e-08, -5.228819292890436e-09, -1.772788813836262e-08,}, {0.9999998323292453, -2.7802280754199847e-08, -4.957533257979059e-09, -1.904046096065164e-08,}, {0.9999998323729795, -2.7819581336589842e-08, -5.144692441497479e-09, -1.9224888362914693e-08,}, {0.9999998325081227, -2.8086518377750516e-08, -5.213541952238939e-09, -1.770298858475437e-08,}, {0.9999998330069749, -2.7972336159772753e-08, -8.192864171344145e-09, -1.8178580739598354e-08,}, {0.9999998330192383, -2.7496868247241832e-08, -8.087059509489084e-09, -1.803932922401032e-08,}, {0.9999998526983208, -2.3181549295886493e-08, -1.4507918856392114e-09, -1.0820370075564826e-08,}, {0.9999998527200421, -2.2983836217222403e-08, -1.303368634914163e-09, -1.1034125123166874e-08,}, {0.9999998541289034, -2.1849563016404378e-08, -3.316421072004626e-09, -1.2874973729422534e-08,},},
 			wantErr: false,
 		},
 	}
@@ -97,6 +98,118 @@ func TestFiltRLS_Run(t *testing.T) {
 	}
 }
 
+func TestFiltRLS_Run_leastSquares(t *testing.T) {
+	rand.Seed(1)
+	//number of samples
+	n := 200
+	L := 4
+	eps := 1e-3
+	//unknown system
+	h := []float64{0.5, -0.3, 0.2, 0.1}
+	var x = make([][]float64, n)
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = floats.Dot(h, x[i]) + rand.NormFloat64()*0.1
+	}
+	af := Must(NewFiltRLS(L, 1.0, eps, nil))
+	if _, _, _, err := af.Run(d, x, WithoutHistory()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	_, _, got := af.GetParams()
+
+	//regularized least squares solution (X^T X + eps I)^-1 X^T d
+	xMat := mat.NewDense(n, L, misc.Floor(x))
+	var a mat.Dense
+	a.Mul(xMat.T(), xMat)
+	for i := 0; i < L; i++ {
+		a.Set(i, i, a.At(i, i)+eps)
+	}
+	var b mat.VecDense
+	b.MulVec(xMat.T(), mat.NewVecDense(n, d))
+	var want mat.VecDense
+	if err := want.SolveVec(&a, &b); err != nil {
+		t.Fatalf("SolveVec() error = %v", err)
+	}
+	if !floats.EqualApprox(got, want.RawVector().Data, 1e-9) {
+		t.Errorf("Run() w = %v, want least squares solution %v", got, want.RawVector().Data)
+	}
+}
+
+func TestFiltRLS_Adapt_allocs(t *testing.T) {
+	L := 16
+	xs := misc.NewNormRandSlice(L + 64)
+	af := Must(NewFiltRLS(L, 0.99, 0.1, nil))
+	i := 0
+	allocs := testing.AllocsPerRun(64, func() {
+		af.Adapt(xs[i+L-1], xs[i:i+L])
+		i++
+	})
+	if allocs != 0 {
+		t.Errorf("Adapt() allocates %v times per sample, want 0", allocs)
+	}
+}
+
+func benchmarkFiltRLSAdapt(b *testing.B, adapt func(af *FiltRLS, d float64, x []float64)) {
+	for _, L := range []int{16, 64, 256} {
+		b.Run(fmt.Sprintf("n=%d", L), func(b *testing.B) {
+			rand.Seed(1)
+			xs := misc.NewNormRandSlice(L + 1024)
+			af := Must(NewFiltRLS(L, 0.99, 0.1, nil)).(*FiltRLS)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				k := i % 1024
+				adapt(af, xs[k+L-1], xs[k:k+L])
+			}
+		})
+	}
+}
+
+func BenchmarkFiltRLS_Adapt(b *testing.B) {
+	benchmarkFiltRLSAdapt(b, (*FiltRLS).Adapt)
+}
+
+func BenchmarkFiltRLS_Adapt_legacy(b *testing.B) {
+	benchmarkFiltRLSAdapt(b, legacyRLSAdapt)
+}
+
+//legacyRLSAdapt is the former implementation of FiltRLS.Adapt, kept as the baseline of the benchmarks.
+//It allocates the intermediate matrices on every sample.
+func legacyRLSAdapt(af *FiltRLS, d float64, x []float64) {
+	w := af.w.RawRowView(0)
+	R1 := mat.NewDense(af.n, af.n, nil)
+	var R2 float64
+	xVec := mat.NewDense(1, af.n, nil)
+	aux1 := mat.NewDense(af.n, 1, nil)
+	aux4 := mat.NewDense(1, af.n, nil)
+	var aux2 float64
+	aux3 := mat.NewDense(af.n, af.n, nil)
+	dwT := mat.NewDense(af.n, 1, nil)
+
+	y := floats.Dot(w, x)
+	e := d - y
+
+	xVec.SetRow(0, x)
+	aux1.Mul(af.rMat, xVec.T())
+	aux2 = floats.Dot(mat.Col(nil, 0, aux1), mat.Row(nil, 0, xVec))
+	R1 = mat.DenseCopyOf(af.rMat)
+	R1.Scale(aux2, R1)
+	aux4.Mul(xVec, af.rMat)
+
+	R2 = af.mu + mat.Dot(aux4.RowView(0), mat.DenseCopyOf(xVec.T()).ColView(0))
+	R1.Scale(1/R2, R1)
+	aux3.Sub(af.rMat, R1)
+	af.rMat.Scale(1/af.mu, aux3)
+	dwT.Mul(af.rMat, xVec.T())
+	dwT.Scale(e, dwT)
+
+	floats.Add(w, mat.Col(nil, 0, dwT))
+}
+
 func ExampleFiltRLS_Run() {
 	rand.Seed(1)
 
@@ -139,7 +252,7 @@ func ExampleFiltRLS_Run() {
 	//print result of filtering (only the last value)
 	fmt.Println(y[n-1], e[n-1], w[n-1])
 	//output:
-	//-0.022095847219796176 -0.017228359604847025 [-0.007496557583694153 -0.0052320251064285695 -0.003709594484657589 -0.0025686444464694106 -0.001768044730851791 -0.0009202325027716438 -0.0021726543974386133 -0.000839361001127372]
+	//-0.014561708130182649 -0.024762498694460552 [-0.04561553890192308 0.0006676767020726659 -0.005866985736164834 0.02965732118895781 0.016737083278976928 0.00933407931818759 -0.024014743977251576 -0.011438286303874775]
 }
 
 func ExampleExploreLearning_rls() {
@@ -180,5 +293,14 @@ func ExampleExploreLearning_rls() {
 	eMin := floats.Min(es)
 	fmt.Printf("the step size mu with the smallest error is %.3f\n", res[eMin])
 	//output:
-	//the step size mu with the smallest error is 0.798
+	//the step size mu with the smallest error is 0.869
+}
+
+func TestNewFiltRLS_invalidParams(t *testing.T) {
+	if _, err := NewFiltRLS(4, 0, 0.1, nil); err == nil {
+		t.Errorf("NewFiltRLS() with mu = 0: error = nil, want error")
+	}
+	if _, err := NewFiltRLS(4, 0.99, 0, nil); err == nil {
+		t.Errorf("NewFiltRLS() with eps = 0: error = nil, want error")
+	}
 }
@@ -0,0 +1,183 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the robust filters.
+const (
+	kindHuberNLMS = "Huber NLMS filter"
+	kindMCCLMS    = "MCC LMS filter"
+	kindLlncosh   = "Llncosh filter"
+)
+
+//FiltHuberNLMS is base struct for NLMS filter with the Huber loss.
+//The error is clipped to the threshold `delta`, so that an impulse in the desired value
+//changes the weights at most as much as an error of `delta`:
+//w += mu / (eps + x·x) * clip(e, -delta, delta) * x.
+//Use NewFiltHuberNLMS to make instance.
+type FiltHuberNLMS struct {
+	filtBase
+	eps   float64
+	delta float64
+}
+
+//NewFiltHuberNLMS is constructor of Huber NLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//the threshold of the Huber loss `delta` and filter weight `w`.
+//A typical `delta` is a few times the standard deviation of the background noise.
+func NewFiltHuberNLMS(n int, mu, eps, delta float64, w []float64) (AdaptiveFilter, error) {
+	base, eps, err := newNLMSBase(kindHuberNLMS, n, mu, eps, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltHuberNLMS{filtBase: base, eps: eps}
+	p.delta, err = p.checkFloatParam(delta, 0, math.MaxFloat64, "delta")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to the clipped error.
+func (af *FiltHuberNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to the clipped error.
+func (af *FiltHuberNLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	nu := af.mu / (af.eps + floats.Dot(x, x))
+	floats.AddScaled(w, nu*clip(e, -af.delta, af.delta), x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to the clipped error.
+func (af *FiltHuberNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltHuberNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltMCCLMS is base struct for LMS filter with the maximum correntropy criterion.
+//The error is weighted by the Gaussian kernel of width `sigma`, so that large errors are ignored:
+//w += mu * exp(-e^2 / (2*sigma^2)) * e * x.
+//Use NewFiltMCCLMS to make instance.
+type FiltMCCLMS struct {
+	filtBase
+	sigma float64
+}
+
+//NewFiltMCCLMS is constructor of MCC LMS filter.
+//This func initialize filter length `n`, update step size `mu`, the kernel width `sigma` and filter weight `w`.
+//`sigma` must be positive. A large `sigma` makes the filter close to LMS.
+func NewFiltMCCLMS(n int, mu, sigma float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindMCCLMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltMCCLMS{filtBase: base}
+	p.sigma, err = p.checkFloatParam(sigma, math.SmallestNonzeroFloat64, math.MaxFloat64, "sigma")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to the error weighted by the kernel.
+func (af *FiltMCCLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to the error weighted by the kernel.
+func (af *FiltMCCLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	k := math.Exp(-e * e / (2 * af.sigma * af.sigma))
+	floats.AddScaled(w, af.mu*k*e, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to the error weighted by the kernel.
+func (af *FiltMCCLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltMCCLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltLlncosh is base struct for Llncosh filter (least lncosh filter).
+//It minimizes the log-cosh of the error, which is quadratic for small errors and linear for large errors:
+//w += mu * tanh(lambda * e) * x.
+//It is the same as FilterLlncosh of padasip.
+//Use NewFiltLlncosh to make instance.
+type FiltLlncosh struct {
+	filtBase
+	lambda float64
+}
+
+//NewFiltLlncosh is constructor of Llncosh filter.
+//This func initialize filter length `n`, update step size `mu`, the cost function parameter `lambda` and filter weight `w`.
+//The default values of padasip are `mu` = 0.01 and `lambda` = 3.
+func NewFiltLlncosh(n int, mu, lambda float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindLlncosh, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltLlncosh{filtBase: base}
+	p.lambda, err = p.checkFloatParam(lambda, math.SmallestNonzeroFloat64, math.MaxFloat64, "lambda")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to tanh of error `e`.
+func (af *FiltLlncosh) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to tanh of error `e`.
+func (af *FiltLlncosh) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.AddScaled(w, af.mu*math.Tanh(af.lambda*e), x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to tanh of error `e`.
+func (af *FiltLlncosh) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltLlncosh) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
@@ -0,0 +1,114 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//alphaStable returns a sample of the symmetric alpha-stable distribution with the characteristic
+//exponent `alpha` in (0, 2] and the dispersion `gamma`, by the Chambers-Mallows-Stuck method.
+func alphaStable(alpha, gamma float64) float64 {
+	v := math.Pi * (rand.Float64() - 0.5)
+	w := rand.ExpFloat64()
+	if alpha == 1 {
+		return gamma * math.Tan(v)
+	}
+	s := math.Sin(alpha*v) / math.Pow(math.Cos(v), 1/alpha) *
+		math.Pow(math.Cos(v-alpha*v)/w, (1-alpha)/alpha)
+	return math.Pow(gamma, 1/alpha) * s
+}
+
+//newImpulsiveSystemData makes the input of white noise and the desired values of the system `h`
+//with the alpha-stable noise.
+func newImpulsiveSystemData(n int, h []float64, alpha, gamma float64) (d []float64, x [][]float64) {
+	d, x = newSystemData(n, h, 0)
+	for i := range d {
+		d[i] += alphaStable(alpha, gamma)
+	}
+	return d, x
+}
+
+//steadyMisalignment returns the mean misalignment of `af` over the latter half of the run in dB.
+func steadyMisalignment(af AdaptiveFilter, d []float64, x [][]float64, h []float64) float64 {
+	var sum float64
+	_, _, _, err := af.Run(d, x, WithHistoryFunc(func(i int, w []float64) error {
+		if i >= len(d)/2 {
+			sum += misalignment(w, h)
+		}
+		return nil
+	}))
+	check(err)
+	return sum / float64(len(d)-len(d)/2)
+}
+
+func TestRobust_alphaStable(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, -0.02, 0.01, 0, 0}
+	d, x := newImpulsiveSystemData(10000, h, 1.2, 0.01)
+
+	//misalignment of NLMS in the same scenario
+	ref := steadyMisalignment(Must(NewFiltNLMS(len(h), 0.1, 1e-3, nil)), d, x, h)
+
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "Huber NLMS", af: Must(NewFiltHuberNLMS(len(h), 0.1, 1e-3, 0.05, nil))},
+		{name: "MCC LMS", af: Must(NewFiltMCCLMS(len(h), 0.02, 0.1, nil))},
+		{name: "Llncosh", af: Must(NewFiltLlncosh(len(h), 0.01, 3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//the impulses in the desired values do not wreck the weights
+			if m := steadyMisalignment(tt.af, d, x, h); m > ref-5 {
+				t.Errorf("%s misalignment = %.1f dB, want 5 dB better than NLMS %.1f dB", tt.name, m, ref)
+			}
+		})
+	}
+}
+
+func TestFiltHuberNLMS_largeThreshold(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(256, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	//with a threshold larger than any error, Huber NLMS is NLMS
+	af := Must(NewFiltHuberNLMS(4, 0.5, 1e-3, math.MaxFloat64, nil))
+	want := Must(NewFiltNLMS(4, 0.5, 1e-3, nil))
+	_, e, _, err := af.Run(d, x)
+	check(err)
+	_, wantE, _, err := want.Run(d, x)
+	check(err)
+	if !floats.Equal(e, wantE) {
+		t.Errorf("Run() e = %v, want %v", e, wantE)
+	}
+}
+
+func TestNewFiltRobust_invalidParams(t *testing.T) {
+	if _, err := NewFiltHuberNLMS(4, 0.1, 1e-3, -1, nil); err == nil {
+		t.Errorf("NewFiltHuberNLMS() with delta = -1: error = nil, want error")
+	}
+	for _, p := range []float64{0, -1} {
+		if _, err := NewFiltMCCLMS(4, 0.1, p, nil); err == nil {
+			t.Errorf("NewFiltMCCLMS() with sigma = %v: error = nil, want error", p)
+		}
+		if _, err := NewFiltLlncosh(4, 0.1, p, nil); err == nil {
+			t.Errorf("NewFiltLlncosh() with lambda = %v: error = nil, want error", p)
+		}
+	}
+}
+
+func ExampleFiltLlncosh_Run() {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newImpulsiveSystemData(5000, h, 1.2, 1e-3)
+	af := Must(NewFiltLlncosh(len(h), 0.01, 3, nil))
+	_, _, _, err := af.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, w := af.GetParams()
+	fmt.Printf("%.2f\n", w)
+	//output:
+	//[0.40 -0.20 0.10 0.06]
+}
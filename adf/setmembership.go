@@ -0,0 +1,191 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the set-membership filters.
+const (
+	kindSMNLMS = "SM-NLMS filter"
+	kindSMAP   = "SM-AP filter"
+)
+
+//checkErrorBound checks that the error bound `gamma` is not negative.
+func (af *filtBase) checkErrorBound(gamma float64) (float64, error) {
+	return af.checkFloatParam(gamma, 0, math.MaxFloat64, "gamma")
+}
+
+//FiltSMNLMS is base struct for SM-NLMS filter (Set-Membership NLMS filter).
+//The weights are updated only when the error exceeds the error bound `gamma`,
+//with the step size which brings the a posteriori error to the bound:
+//w += mu * (1 - gamma/|e|) / (eps + x·x) * e * x.
+//Use NewFiltSMNLMS to make instance.
+type FiltSMNLMS struct {
+	filtBase
+	eps   float64
+	gamma float64
+}
+
+//NewFiltSMNLMS is constructor of SM-NLMS filter.
+//This func initialize filter length `n`, update step size `mu`, regularization term `eps`,
+//the error bound `gamma` and filter weight `w`.
+//`mu` = 1 is the standard SM-NLMS. A typical `gamma` is about sqrt(5) times the standard deviation of the noise.
+func NewFiltSMNLMS(n int, mu, eps, gamma float64, w []float64) (AdaptiveFilter, error) {
+	base, eps, err := newNLMSBase(kindSMNLMS, n, mu, eps, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltSMNLMS{filtBase: base, eps: eps}
+	p.gamma, err = p.checkErrorBound(gamma)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights if |e| exceeds the error bound.
+func (af *FiltSMNLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights if |e| exceeds the error bound.
+func (af *FiltSMNLMS) Step(d float64, x []float64) (y, e float64) {
+	y, e, _ = af.step(d, x)
+	return y, e
+}
+
+//step is Step which also reports whether the weights were updated.
+func (af *FiltSMNLMS) step(d float64, x []float64) (y, e float64, updated bool) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	if math.Abs(e) <= af.gamma {
+		return y, e, false
+	}
+	nu := af.mu * (1 - af.gamma/math.Abs(e)) / (af.eps + floats.Dot(x, x))
+	floats.AddScaled(w, nu*e, x)
+	return y, e, true
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights if |e| exceeds the error bound.
+//Use WithUpdateReport to know which samples triggered an update.
+func (af *FiltSMNLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	var updated bool
+	step := func(d float64, x []float64) (y, e float64) {
+		y, e, updated = af.step(d, x)
+		return y, e
+	}
+	return af.runSteps(d, x, opts, step, &runHooks{
+		init: func(N int, hist *historyRecorder[float64]) {
+			hist.initUpdates(N)
+		},
+		after: func(i int, hist *historyRecorder[float64]) error {
+			hist.recordUpdate(i, updated)
+			return nil
+		},
+	})
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSMNLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltSMAP is base struct for SM-AP filter (Set-Membership Affine Projection filter).
+//The weights are updated only when the error of the new sample exceeds the error bound `gamma`.
+//The update projects the weights so that the a posteriori error of the new sample is on the bound
+//and the a posteriori errors of the past samples in the window are kept,
+//which is the simple choice of the constraint vector of Werner and Diniz.
+//Use NewFiltSMAP to make instance.
+type FiltSMAP struct {
+	apBase
+	gamma float64
+	//eVec is the errors used for the update.
+	eVec *mat.Dense
+}
+
+//NewFiltSMAP is constructor of SM-AP filter.
+//This func initialize filter length `n`, update step size `mu`, projection order `order`,
+//regularization term `eps`, the error bound `gamma` and filter weight `w`.
+//`mu` = 1 is the standard SM-AP.
+func NewFiltSMAP(n int, mu float64, order int, eps, gamma float64, w []float64) (AdaptiveFilter, error) {
+	ap, err := NewFiltAP(n, mu, order, eps, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltSMAP{apBase: ap.(*FiltAP).apBase}
+	p.kind = kindSMAP
+	p.gamma, err = p.checkErrorBound(gamma)
+	if err != nil {
+		return nil, err
+	}
+	p.eVec = mat.NewDense(1, order, nil)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights if |e| exceeds the error bound.
+func (af *FiltSMAP) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights if |e| exceeds the error bound.
+func (af *FiltSMAP) Step(d float64, x []float64) (y, e float64) {
+	y, e, _, err := af.smStep(d, x)
+	if err != nil {
+		panic(err)
+	}
+	return y, e
+}
+
+//smStep is Step which also reports whether the weights were updated and the error of solving the linear system.
+func (af *FiltSMAP) smStep(d float64, x []float64) (y, e float64, updated bool, err error) {
+	y, e = af.shift(d, x)
+	if math.Abs(e) <= af.gamma {
+		return y, e, false, nil
+	}
+	af.eVec.Zero()
+	af.eVec.Set(0, 0, (1-af.gamma/math.Abs(e))*e)
+	return y, e, true, af.update(af.eVec, af.mu)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights if |e| exceeds the error bound.
+//Use WithUpdateReport to know which samples triggered an update.
+func (af *FiltSMAP) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	var updated bool
+	var stepErr error
+	step := func(d float64, x []float64) (y, e float64) {
+		y, e, updated, stepErr = af.smStep(d, x)
+		return y, e
+	}
+	return af.runSteps(d, x, opts, step, &runHooks{
+		init: func(N int, hist *historyRecorder[float64]) {
+			hist.initUpdates(N)
+		},
+		after: func(i int, hist *historyRecorder[float64]) error {
+			if stepErr != nil {
+				return stepErr
+			}
+			hist.recordUpdate(i, updated)
+			return nil
+		},
+	})
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSMAP) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.apBase = af.clone()
+	altaf.eVec = mat.NewDense(1, af.order, nil)
+	return &altaf
+}
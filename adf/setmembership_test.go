@@ -0,0 +1,110 @@
+package adf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestSetMembership_Run(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05, -0.02, 0.01, 0, 0}
+	const noise = 0.01
+	d, x := newSystemData(4000, h, noise)
+	gamma := math.Sqrt(5) * noise
+
+	//misalignment of NLMS in the same scenario
+	nlms := Must(NewFiltNLMS(len(h), 1, 1e-3, nil))
+	_, _, _, err := nlms.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, wNLMS := nlms.GetParams()
+	ref := misalignment(wNLMS, h)
+
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "SM-NLMS", af: Must(NewFiltSMNLMS(len(h), 1, 1e-3, gamma, nil))},
+		{name: "SM-AP", af: Must(NewFiltSMAP(len(h), 1, 2, 1e-3, gamma, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r UpdateReport
+			_, e, _, err := tt.af.Run(d, x, WithUpdateReport(&r))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(r.Updated) != len(d) {
+				t.Fatalf("Run() len(Updated) = %d, want %d", len(r.Updated), len(d))
+			}
+			count := 0
+			for i, u := range r.Updated {
+				if u {
+					count++
+				}
+				//the weights are updated only when the error exceeds the bound
+				if u != (math.Abs(e[i]) > gamma) {
+					t.Fatalf("Run() Updated[%d] = %v, but |e| = %v and gamma = %v", i, u, math.Abs(e[i]), gamma)
+				}
+			}
+			if r.Count != count || r.Ratio != float64(count)/float64(len(d)) {
+				t.Errorf("Run() Count = %d, Ratio = %v, want %d, %v", r.Count, r.Ratio, count, float64(count)/float64(len(d)))
+			}
+			//most samples do not need an update in the steady state
+			if r.Ratio > 0.2 {
+				t.Errorf("Run() update ratio = %v, want <= 0.2", r.Ratio)
+			}
+			_, _, w := tt.af.GetParams()
+			if m := misalignment(w, h); m > ref {
+				t.Errorf("%s misalignment = %.1f dB, want not worse than NLMS %.1f dB", tt.name, m, ref)
+			}
+		})
+	}
+}
+
+func TestSetMembership_zeroBound(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(256, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+		want AdaptiveFilter
+	}{
+		//with gamma = 0 every sample triggers the update of NLMS
+		{name: "SM-NLMS", af: Must(NewFiltSMNLMS(4, 1, 1e-3, 0, nil)), want: Must(NewFiltNLMS(4, 1, 1e-3, nil))},
+		//with gamma = 0 and order 1, SM-AP is AP
+		{name: "SM-AP", af: Must(NewFiltSMAP(4, 1, 1, 1e-3, 0, nil)), want: Must(NewFiltAP(4, 1, 1, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r UpdateReport
+			y, _, _, err := tt.af.Run(d, x, WithUpdateReport(&r))
+			check(err)
+			wantY, _, _, err := tt.want.Run(d, x)
+			check(err)
+			if !floats.EqualApprox(y, wantY, 1e-12) {
+				t.Errorf("Run() y differs from %s", tt.want.GetKindName())
+			}
+			if r.Ratio != 1 {
+				t.Errorf("Run() update ratio = %v, want 1", r.Ratio)
+			}
+		})
+	}
+}
+
+func ExampleWithUpdateReport() {
+	rand.Seed(1)
+	noise := 0.01
+	d, x := newSystemData(4000, []float64{0.4, -0.2, 0.1, 0.05}, noise)
+
+	af := Must(NewFiltSMNLMS(4, 1, 1e-3, math.Sqrt(5)*noise, nil))
+	var r UpdateReport
+	_, _, _, err := af.Run(d, x, WithoutHistory(), WithUpdateReport(&r))
+	check(err)
+	fmt.Printf("updated %d of %d samples (%.1f%%)\n", r.Count, len(r.Updated), 100*r.Ratio)
+	//output:
+	//updated 177 of 4000 samples (4.4%)
+}
@@ -0,0 +1,157 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the sign LMS filters.
+const (
+	kindSELMS = "SE-LMS filter"
+	kindSDLMS = "SD-LMS filter"
+	kindSSLMS = "SS-LMS filter"
+)
+
+//FiltSELMS is base struct for sign-error LMS filter.
+//The weights are updated with the sign of the error: w += mu * sign(e) * x.
+//Use NewFiltSELMS to make instance.
+type FiltSELMS struct {
+	filtBase
+}
+
+//NewFiltSELMS is constructor of sign-error LMS filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+func NewFiltSELMS(n int, mu float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindSELMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltSELMS{filtBase: base}, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to the sign of error `e`.
+func (af *FiltSELMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to the sign of error `e`.
+func (af *FiltSELMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.AddScaled(w, af.mu*sign(e), x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to the sign of error `e`.
+func (af *FiltSELMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSELMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltSDLMS is base struct for sign-data LMS filter.
+//The weights are updated with the sign of the input: w += mu * e * sign(x).
+//Use NewFiltSDLMS to make instance.
+type FiltSDLMS struct {
+	filtBase
+}
+
+//NewFiltSDLMS is constructor of sign-data LMS filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+func NewFiltSDLMS(n int, mu float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindSDLMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltSDLMS{filtBase: base}, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` and the sign of input `x`.
+func (af *FiltSDLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` and the sign of input `x`.
+func (af *FiltSDLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	for i := 0; i < len(x); i++ {
+		w[i] += af.mu * e * sign(x[i])
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` and the sign of input `x`.
+func (af *FiltSDLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSDLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltSSLMS is base struct for sign-sign LMS filter.
+//The weights are updated with the signs of the error and the input: w += mu * sign(e) * sign(x).
+//The update needs no multiplication except for `mu`.
+//Use NewFiltSSLMS to make instance.
+type FiltSSLMS struct {
+	filtBase
+}
+
+//NewFiltSSLMS is constructor of sign-sign LMS filter.
+//This func initialize filter length `n`, update step size `mu` and filter weight `w`.
+func NewFiltSSLMS(n int, mu float64, w []float64) (AdaptiveFilter, error) {
+	base, err := newLMSBase(kindSSLMS, n, mu, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltSSLMS{filtBase: base}, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to the signs of error `e` and input `x`.
+func (af *FiltSSLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to the signs of error `e` and input `x`.
+func (af *FiltSSLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	s := af.mu * sign(e)
+	for i := 0; i < len(x); i++ {
+		w[i] += s * sign(x[i])
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to the signs of error `e` and input `x`.
+func (af *FiltSSLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSSLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
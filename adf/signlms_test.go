@@ -0,0 +1,84 @@
+package adf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestSignLMS_Run(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(4096, h, 0.01)
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "SE-LMS", af: Must(NewFiltSELMS(len(h), 0.002, nil))},
+		{name: "SD-LMS", af: Must(NewFiltSDLMS(len(h), 0.005, nil))},
+		{name: "SS-LMS", af: Must(NewFiltSSLMS(len(h), 0.001, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, e, wHist, err := tt.af.Run(d, x)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(e) != len(d) || len(wHist) != len(d) {
+				t.Fatalf("Run() len(e) = %d, len(wHist) = %d, want %d", len(e), len(wHist), len(d))
+			}
+			if _, _, w := tt.af.GetParams(); !floats.EqualApprox(w, h, 0.01) {
+				t.Errorf("Run() w = %v, want %v", w, h)
+			}
+		})
+	}
+}
+
+func TestSignLMS_invalidMu(t *testing.T) {
+	constructors := map[string]func(n int, mu float64, w []float64) (AdaptiveFilter, error){
+		"SE-LMS": NewFiltSELMS,
+		"SD-LMS": NewFiltSDLMS,
+		"SS-LMS": NewFiltSSLMS,
+	}
+	for name, newAF := range constructors {
+		for _, mu := range []float64{-0.1, 2.1} {
+			if _, err := newAF(4, mu, nil); err == nil {
+				t.Errorf("%s with mu = %v: error = nil, want error", name, mu)
+			}
+		}
+		if _, err := newAF(4, 0.1, make([]float64, 3)); err == nil {
+			t.Errorf("%s with len(w) = 3: error = nil, want error", name)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	for _, tt := range []struct{ v, want float64 }{{-2.5, -1}, {0, 0}, {0.1, 1}} {
+		if got := sign(tt.v); got != tt.want {
+			t.Errorf("sign(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func ExampleExploreLearning_sslms() {
+	rand.Seed(1)
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(1024, h, 0.01)
+
+	//the sign-sign LMS filter drops into ExploreLearning like FiltLMS
+	af := Must(NewFiltSSLMS(len(h), 0.001, nil))
+	es, mus, err := ExploreLearning(af, d, x, 0.001, 0.02, 20, 0.5, 1, "MSE", nil)
+	check(err)
+
+	res := make(map[float64]float64, len(es))
+	for i := 0; i < len(es); i++ {
+		res[es[i]] = mus[i]
+	}
+	eMin := floats.Min(es)
+	fmt.Printf("the step size mu with the smallest error is %.4f\n", res[eMin])
+	//output:
+	//the step size mu with the smallest error is 0.0020
+}
@@ -0,0 +1,117 @@
+package adf
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestFilter_MarshalBinary(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 128
+	L := 8
+	//input value
+	var x = make([][]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, rand.NormFloat64())
+		x[i] = append([]float64{}, xRow...)
+		d[i] = 0.5*x[i][L-1] - 0.25*x[i][L-2] + rand.NormFloat64()*0.1
+	}
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "LMS", af: Must(NewFiltLMS(L, 0.05, nil))},
+		{name: "NLMS", af: Must(NewFiltNLMS(L, 0.5, 1e-5, nil))},
+		{name: "RLS", af: Must(NewFiltRLS(L, 0.99, 0.1, nil))},
+		{name: "AP", af: Must(NewFiltAP(L, 0.5, 4, 1e-3, nil))},
+	}
+	type marshaler interface {
+		MarshalBinary() ([]byte, error)
+		MarshalJSON() ([]byte, error)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//adapt to the first half and save the state
+			if _, _, _, err := tt.af.Run(d[:n/2], x[:n/2]); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			bin, err := tt.af.(marshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			js, err := tt.af.(marshaler).MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			fromBin, err := UnmarshalFilterBinary(bin)
+			if err != nil {
+				t.Fatalf("UnmarshalFilterBinary() error = %v", err)
+			}
+			fromJSON, err := UnmarshalFilterJSON(js)
+			if err != nil {
+				t.Fatalf("UnmarshalFilterJSON() error = %v", err)
+			}
+			if reflect.TypeOf(fromBin) != reflect.TypeOf(tt.af) || reflect.TypeOf(fromJSON) != reflect.TypeOf(tt.af) {
+				t.Fatalf("restored types = %T, %T, want %T", fromBin, fromJSON, tt.af)
+			}
+
+			//resume the adaptation on the second half
+			wantY, wantE, wantW, err := tt.af.Run(d[n/2:], x[n/2:])
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			for _, restored := range []AdaptiveFilter{fromBin, fromJSON} {
+				y, e, w, err := restored.Run(d[n/2:], x[n/2:])
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				if !reflect.DeepEqual(y, wantY) || !reflect.DeepEqual(e, wantE) || !reflect.DeepEqual(w, wantW) {
+					t.Errorf("restored %v does not resume the adaptation of the original filter", restored.GetKindName())
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_UnmarshalBinary_invalid(t *testing.T) {
+	lms := Must(NewFiltLMS(4, 0.1, []float64{1, 2, 3, 4}))
+	bin, err := lms.(*FiltLMS).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	if err := new(FiltNLMS).UnmarshalBinary(bin); err == nil {
+		t.Errorf("UnmarshalBinary() of LMS state into FiltNLMS: error = nil, want error")
+	}
+
+	tests := []struct {
+		name  string
+		state filterState
+	}{
+		{name: "unknown version", state: filterState{Version: stateVersion + 1, Kind: kindLMS, N: 1, Mu: 0.1, W: []float64{0}}},
+		{name: "unknown kind", state: filterState{Version: stateVersion, Kind: "unknown filter", N: 1, Mu: 0.1, W: []float64{0}}},
+		{name: "length mismatch", state: filterState{Version: stateVersion, Kind: kindLMS, N: 2, Mu: 0.1, W: []float64{0}}},
+		{name: "invalid mu", state: filterState{Version: stateVersion, Kind: kindLMS, N: 1, Mu: 10, W: []float64{0}}},
+		{name: "missing rMat", state: filterState{Version: stateVersion, Kind: kindRLS, N: 1, Mu: 0.99, Eps: 0.1, W: []float64{0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			js, err := json.Marshal(tt.state)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if _, err := UnmarshalFilterJSON(js); err == nil {
+				t.Errorf("UnmarshalFilterJSON() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package adf
+
+import (
+	"errors"
+)
+
+//Stream feeds an adaptive filter sample by sample.
+//It owns the tap-delay line of the filter, so the caller does not need to build the input matrix.
+//Use NewStream to make instance.
+type Stream struct {
+	af  AdaptiveFilter
+	n   int
+	pos int
+	//buf holds the delay line twice in a row,
+	//so that the latest `n` samples are always available as a contiguous slice.
+	buf []float64
+}
+
+//NewStream is constructor of Stream.
+//The length of the delay line is the filter length `n` of `af`.
+func NewStream(af AdaptiveFilter) *Stream {
+	n, _, _ := af.GetParams()
+	s := new(Stream)
+	s.af = af
+	s.n = n
+	s.buf = make([]float64, 2*n)
+	return s
+}
+
+//push writes the new sample `x` to the delay line and returns the current input slice.
+//The oldest sample comes first and the newest sample comes last, as in the rows of the input matrix of Run.
+func (s *Stream) push(x float64) []float64 {
+	s.buf[s.pos] = x
+	s.buf[s.pos+s.n] = x
+	s.pos++
+	if s.pos == s.n {
+		s.pos = 0
+	}
+	return s.buf[s.pos : s.pos+s.n]
+}
+
+//ProcessSample pushes the new input sample `x` to the delay line,
+//calculates the estimated value `y` and the error `e` against the desired value `d`,
+//and updates filter weights according to error `e`.
+func (s *Stream) ProcessSample(d, x float64) (y, e float64) {
+	return step(s.af, d, s.push(x))
+}
+
+//ProcessBlock calls ProcessSample for each sample of the block.
+//The estimated values and the errors are written to `y` and `e`.
+//All slices must have the same length.
+func (s *Stream) ProcessBlock(d, x []float64, y, e []float64) error {
+	N := len(x)
+	if len(d) != N || len(y) != N || len(e) != N {
+		return errors.New("the length of slice d, x, y and e must agree")
+	}
+	for i := 0; i < N; i++ {
+		y[i], e[i] = s.ProcessSample(d[i], x[i])
+	}
+	return nil
+}
+
+//Reset clears the delay line. The filter weights are not changed.
+func (s *Stream) Reset() {
+	for i := range s.buf {
+		s.buf[i] = 0
+	}
+	s.pos = 0
+}
+
+//Filter returns the adaptive filter driven by the stream.
+func (s *Stream) Filter() AdaptiveFilter {
+	return s.af
+}
@@ -0,0 +1,107 @@
+package adf
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestStream_ProcessBlock(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 256
+	L := 8
+	//input signal
+	var xs = make([]float64, n)
+	//input value
+	var x = make([][]float64, n)
+	//desired value
+	var d = make([]float64, n)
+	var xRow = make([]float64, L)
+	for i := 0; i < n; i++ {
+		xs[i] = rand.NormFloat64()
+		xRow = misc.Unset(xRow, 0)
+		xRow = append(xRow, xs[i])
+		x[i] = append([]float64{}, xRow...)
+		d[i] = 0.5*x[i][L-1] - 0.25*x[i][L-3] + rand.NormFloat64()*0.1
+	}
+	tests := []struct {
+		name string
+		af   func() AdaptiveFilter
+	}{
+		{
+			name: "LMS",
+			af:   func() AdaptiveFilter { return Must(NewFiltLMS(L, 0.05, nil)) },
+		},
+		{
+			name: "NLMS",
+			af:   func() AdaptiveFilter { return Must(NewFiltNLMS(L, 0.5, 1e-5, nil)) },
+		},
+		{
+			name: "RLS",
+			af:   func() AdaptiveFilter { return Must(NewFiltRLS(L, 0.99, 0.1, nil)) },
+		},
+		{
+			name: "AP",
+			af:   func() AdaptiveFilter { return Must(NewFiltAP(L, 0.5, 4, 1e-3, nil)) },
+		},
+		{
+			//the wrapper hides Step, so that the stream falls back to Predict and Adapt
+			name: "without Stepper",
+			af:   func() AdaptiveFilter { return struct{ AdaptiveFilter }{Must(NewFiltLMS(L, 0.05, nil))} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantY, wantE, _, err := tt.af().Run(d, x)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			s := NewStream(tt.af())
+			y := make([]float64, n)
+			e := make([]float64, n)
+			if err := s.ProcessBlock(d, xs, y, e); err != nil {
+				t.Fatalf("ProcessBlock() error = %v", err)
+			}
+			if !floats.EqualApprox(y, wantY, 1e-9) {
+				t.Errorf("ProcessBlock() y = %v, want %v", y, wantY)
+			}
+			if !floats.EqualApprox(e, wantE, 1e-9) {
+				t.Errorf("ProcessBlock() e = %v, want %v", e, wantE)
+			}
+		})
+	}
+}
+
+func TestStream_ProcessBlock_lengthMismatch(t *testing.T) {
+	s := NewStream(Must(NewFiltLMS(4, 0.1, nil)))
+	if err := s.ProcessBlock(make([]float64, 4), make([]float64, 3), make([]float64, 4), make([]float64, 4)); err == nil {
+		t.Errorf("ProcessBlock() error = nil, want error")
+	}
+}
+
+func TestStream_ProcessSample_allocs(t *testing.T) {
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "LMS", af: Must(NewFiltLMS(16, 0.01, nil))},
+		{name: "NLMS", af: Must(NewFiltNLMS(16, 0.1, 1e-3, nil))},
+		{name: "RLS", af: Must(NewFiltRLS(16, 0.99, 0.1, nil))},
+		{name: "AP", af: Must(NewFiltAP(16, 0.1, 4, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStream(tt.af)
+			allocs := testing.AllocsPerRun(100, func() {
+				s.ProcessSample(rand.NormFloat64(), rand.NormFloat64())
+			})
+			if allocs != 0 {
+				t.Errorf("ProcessSample() allocates %v times per sample, want 0", allocs)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kindSWRLS is the kind name of FiltSWRLS.
+const kindSWRLS = "sliding-window RLS filter"
+
+//FiltSWRLS is base struct for sliding-window RLS filter.
+//The weights are the regularized least-squares solution over the last `windowLen` samples,
+//	w = (X^T X + eps I)^-1 X^T d,
+//where the rows of X and d are the samples in the window.
+//Unlike FiltRLS, the samples older than the window are forgotten completely,
+//so the filter tracks an abrupt change of the system within `windowLen` samples.
+//Each sample is added to the inverse correlation matrix by the RLS update
+//and the sample leaving the window is removed by the downdate.
+//The step size `mu` is fixed to 1, that is there is no forgetting inside the window.
+//Use NewFiltSWRLS to make instance.
+type FiltSWRLS struct {
+	filtBase
+	eps       float64
+	windowLen int
+	rMat      *mat.Dense
+	//xBuf and dBuf are the circular buffers of the samples in the window. head is the index of the oldest sample.
+	xBuf  *mat.Dense
+	dBuf  []float64
+	head  int
+	count int
+	//k is the workspace for the gain vector.
+	k []float64
+}
+
+//NewFiltSWRLS is constructor of sliding-window RLS filter.
+//This func initialize filter length `n`, the window length `windowLen`, small enough value `eps`, and filter weight `w`.
+//`eps` is the regularization term as NewFiltRLS, that is the inverse correlation matrix starts from the identity matrix divided by `eps`.
+//It is never forgotten, and keeps the solution unique while the window does not excite all the taps.
+func NewFiltSWRLS(n int, windowLen int, eps float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltSWRLS)
+	p.kind = kindSWRLS
+	p.n = n
+	p.muMin = 1
+	p.muMax = 1
+	p.mu = 1
+	p.windowLen, err = p.checkIntParam(windowLen, 1, 1<<30, "windowLen")
+	if err != nil {
+		return nil, err
+	}
+	p.eps, err = p.checkFloatParam(eps, math.SmallestNonzeroFloat64, 1, "eps")
+	if err != nil {
+		return nil, err
+	}
+	err = p.initWeights(w, n)
+	if err != nil {
+		return nil, err
+	}
+	p.xBuf = mat.NewDense(windowLen, n, nil)
+	p.dBuf = make([]float64, windowLen)
+	p.k = make([]float64, n)
+	p.initRMat()
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *FiltSWRLS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights by adding the sample to the window and removing the oldest one.
+func (af *FiltSWRLS) Step(d float64, x []float64) (y, e float64) {
+	y = floats.Dot(af.w.RawRowView(0), x)
+	e = d - y
+	af.update(d, x, 1)
+	if af.count == af.windowLen {
+		xOld := af.xBuf.RawRowView(af.head)
+		af.update(af.dBuf[af.head], xOld, -1)
+	} else {
+		af.count++
+	}
+	//the new sample takes the place of the oldest one
+	af.xBuf.SetRow(af.head, x)
+	af.dBuf[af.head] = d
+	af.head = (af.head + 1) % af.windowLen
+	return y, e
+}
+
+//update adds (`sign` = 1) or removes (`sign` = -1) the sample `d` and `x`
+//to the inverse correlation matrix `rMat` and the filter weights.
+//
+//With k = R x, the inverse correlation matrix is updated by the rank-one update
+//	R = R - sign k k^T / (1 + sign x^T k)
+//and the weights by
+//	w = w + sign (d - w^T x) k / (1 + sign x^T k).
+func (af *FiltSWRLS) update(d float64, x []float64, sign float64) {
+	w := af.w.RawRowView(0)
+	k := af.k
+	for i := 0; i < af.n; i++ {
+		k[i] = floats.Dot(af.rMat.RawRowView(i), x)
+	}
+	den := 1 + sign*floats.Dot(x, k)
+	for i := 0; i < af.n; i++ {
+		floats.AddScaled(af.rMat.RawRowView(i), -sign*k[i]/den, k)
+	}
+	floats.AddScaled(w, sign*(d-floats.Dot(w, x))/den, k)
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights over the sliding window.
+func (af *FiltSWRLS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Reset sets the filter weights to zeros, empties the window and
+//initializes the inverse correlation matrix `rMat` to the identity matrix divided by `eps`.
+func (af *FiltSWRLS) Reset() {
+	af.filtBase.Reset()
+	af.xBuf.Zero()
+	for i := range af.dBuf {
+		af.dBuf[i] = 0
+	}
+	af.head = 0
+	af.count = 0
+	af.initRMat()
+}
+
+//initRMat initializes the inverse correlation matrix `rMat` to the identity matrix divided by `eps`.
+func (af *FiltSWRLS) initRMat() {
+	var Rs = make([]float64, af.n*af.n)
+	for i := 0; i < af.n; i++ {
+		Rs[i*(af.n+1)] = 1 / af.eps
+	}
+	af.rMat = mat.NewDense(af.n, af.n, Rs)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltSWRLS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.rMat = mat.DenseCopyOf(af.rMat)
+	altaf.xBuf = mat.DenseCopyOf(af.xBuf)
+	altaf.dBuf = append([]float64{}, af.dBuf...)
+	altaf.k = make([]float64, len(af.k))
+	return &altaf
+}
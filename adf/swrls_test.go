@@ -0,0 +1,114 @@
+package adf
+
+import (
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//windowLS returns the regularized least-squares solution (X^T X + eps I)^-1 X^T d.
+func windowLS(d []float64, x [][]float64, eps float64) []float64 {
+	n := len(x[0])
+	a := mat.NewDense(n, n, nil)
+	b := mat.NewVecDense(n, nil)
+	for i := range x {
+		xv := mat.NewVecDense(n, x[i])
+		a.RankOne(a, 1, xv, xv)
+		b.AddScaledVec(b, d[i], xv)
+	}
+	for i := 0; i < n; i++ {
+		a.Set(i, i, a.At(i, i)+eps)
+	}
+	var w mat.VecDense
+	check(w.SolveVec(a, b))
+	return w.RawVector().Data
+}
+
+func TestFiltSWRLS_windowLS(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(300, []float64{0.4, -0.2, 0.1, 0.05}, 0.1)
+	const windowLen = 50
+	const eps = 0.1
+	af := Must(NewFiltSWRLS(4, windowLen, eps, nil))
+	for i := range d {
+		af.Adapt(d[i], x[i])
+		//the weights are the solution over the last `windowLen` samples
+		start := i + 1 - windowLen
+		if start < 0 {
+			start = 0
+		}
+		want := windowLS(d[start:i+1], x[start:i+1], eps)
+		_, _, w := af.GetParams()
+		if !floats.EqualApprox(w, want, 1e-9) {
+			t.Fatalf("w after sample %d = %v, want %v", i, w, want)
+		}
+	}
+}
+
+func TestFiltSWRLS_tracking(t *testing.T) {
+	rand.Seed(1)
+	h1 := []float64{0.4, -0.2, 0.1, 0.05, 0.3, -0.1, 0.02, 0.01}
+	h2 := []float64{-0.3, 0.1, 0.25, -0.05, 0.1, 0.2, -0.02, 0}
+	const change = 3000
+	d1, x1 := newSystemData(change, h1, 0.01)
+	d2, x2 := newSystemData(1000, h2, 0.01)
+	d := append(d1, d2...)
+	x := append(x1, x2...)
+
+	const windowLen = 100
+	//FiltRLS with mu = 0.999 remembers about 1/(1-mu) = 1000 samples
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "sliding-window RLS", af: Must(NewFiltSWRLS(len(h1), windowLen, 0.01, nil))},
+		{name: "RLS", af: Must(NewFiltRLS(len(h1), 0.999, 0.01, nil))},
+	}
+	var mis []float64
+	for _, tt := range tests {
+		var w []float64
+		_, _, _, err := tt.af.Run(d, x, WithHistoryFunc(func(i int, wi []float64) error {
+			if i == change+windowLen+1 {
+				w = append([]float64{}, wi...)
+			}
+			return nil
+		}))
+		check(err)
+		mis = append(mis, misalignment(w, h2))
+	}
+	//the window is filled with the samples of the new system
+	if mis[0] > -30 {
+		t.Errorf("sliding-window RLS misalignment %d samples after the change = %.1f dB, want < -30 dB", windowLen+1, mis[0])
+	}
+	if mis[1] < mis[0]+20 {
+		t.Errorf("RLS misalignment = %.1f dB, want 20 dB worse than sliding-window RLS %.1f dB", mis[1], mis[0])
+	}
+}
+
+func TestFiltSWRLS_Reset(t *testing.T) {
+	rand.Seed(1)
+	d, x := newSystemData(64, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	af := Must(NewFiltSWRLS(4, 16, 0.1, nil))
+	_, want, _, err := af.Run(d, x)
+	check(err)
+	af.Reset()
+	_, e, _, err := af.Run(d, x)
+	check(err)
+	if !floats.Equal(e, want) {
+		t.Errorf("Run() after Reset() e = %v, want %v", e, want)
+	}
+}
+
+func TestNewFiltSWRLS_invalidParams(t *testing.T) {
+	if _, err := NewFiltSWRLS(4, 0, 0.1, nil); err == nil {
+		t.Errorf("NewFiltSWRLS() with windowLen = 0: error = nil, want error")
+	}
+	if _, err := NewFiltSWRLS(4, 16, 2, nil); err == nil {
+		t.Errorf("NewFiltSWRLS() with eps = 2: error = nil, want error")
+	}
+	if _, err := NewFiltSWRLS(4, 16, 0, nil); err == nil {
+		t.Errorf("NewFiltSWRLS() with eps = 0: error = nil, want error")
+	}
+}
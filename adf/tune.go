@@ -0,0 +1,223 @@
+package adf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/tetsuzawa/go-adflib/internal/adfutil"
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+//Params is a set of hyperparameters by name, for example "n", "mu", "eps" or "order".
+type Params map[string]float64
+
+//Int returns the parameter `name` rounded to the nearest integer.
+//It is useful for integer parameters such as the filter length `n` and the projection order.
+func (p Params) Int(name string) int {
+	return int(math.Round(p[name]))
+}
+
+//Dimension is the set of candidate values of a hyperparameter.
+//Use Lin, Log or Discrete to make instance.
+type Dimension struct {
+	values []float64
+	sample func(r *rand.Rand) float64
+}
+
+//Lin makes Dimension of `steps` values evenly spaced between `start` and `end`.
+//In random search, values are drawn uniformly from [start, end].
+func Lin(start, end float64, steps int) Dimension {
+	return Dimension{
+		values: misc.LinSpace(start, end, steps),
+		sample: func(r *rand.Rand) float64 {
+			return start + r.Float64()*(end-start)
+		},
+	}
+}
+
+//Log makes Dimension of `steps` values evenly spaced on a log scale between `start` and `end`.
+//`start` and `end` must be positive.
+//In random search, values are drawn log-uniformly from [start, end].
+func Log(start, end float64, steps int) Dimension {
+	values := misc.LinSpace(math.Log(start), math.Log(end), steps)
+	for i := range values {
+		values[i] = math.Exp(values[i])
+	}
+	return Dimension{
+		values: values,
+		sample: func(r *rand.Rand) float64 {
+			return math.Exp(math.Log(start) + r.Float64()*(math.Log(end)-math.Log(start)))
+		},
+	}
+}
+
+//Discrete makes Dimension of the given values.
+//In random search, one of the values is drawn uniformly.
+func Discrete(values ...float64) Dimension {
+	values = append([]float64{}, values...)
+	return Dimension{
+		values: values,
+		sample: func(r *rand.Rand) float64 {
+			return values[r.Intn(len(values))]
+		},
+	}
+}
+
+//SearchSpace maps the names of hyperparameters to their candidate values.
+type SearchSpace map[string]Dimension
+
+//TuneConfig is the configuration of Tune.
+type TuneConfig struct {
+	//FitConfig is used to train and score every candidate. See Fit.
+	FitConfig
+	//Samples is the number of randomly sampled candidates.
+	//If it is 0, every combination of the grid values is tested.
+	Samples int
+	//Seed is the seed of the random search.
+	Seed int64
+	//Workers is the maximum number of candidates tested at the same time.
+	//If it is smaller than 1, GOMAXPROCS is used.
+	Workers int
+}
+
+//TuneResult is the result of a candidate of Tune.
+type TuneResult struct {
+	//Params is the hyperparameters of the candidate.
+	Params Params
+	//Score is the smallest validation error of the candidate.
+	Score float64
+	//Fit is the learning record of the candidate.
+	Fit *FitResult
+	//Err is the error from the constructor or the training.
+	//For example, a parameter out of the valid range of the filter is reported here.
+	Err error
+}
+
+//Tune searches the hyperparameters of an adaptive filter.
+//
+//`newFilter` makes the filter from the hyperparameters of a candidate.
+//`d` is the desired signal and `x` is the input signal, one value per sample.
+//The input matrix is built from `x` for the filter length `n` of every candidate,
+//so the filter length itself can be tuned.
+//
+//Every candidate is trained and scored by Fit with `cfg.FitConfig`, that is on the held-out part of the data.
+//The results are sorted by the score in ascending order. Candidates with an error come last.
+//An error is returned only when the arguments are invalid.
+func Tune(newFilter func(p Params) (AdaptiveFilter, error), d, x []float64,
+	space SearchSpace, cfg TuneConfig) ([]TuneResult, error) {
+	return TuneContext(context.Background(), newFilter, d, x, space, cfg)
+}
+
+//TuneContext is Tune with cancellation.
+//When `ctx` is done, the remaining candidates are not trained and the error of `ctx` is returned.
+func TuneContext(ctx context.Context, newFilter func(p Params) (AdaptiveFilter, error), d, x []float64,
+	space SearchSpace, cfg TuneConfig) ([]TuneResult, error) {
+	if len(d) != len(x) {
+		return nil, errors.New("the length of slice d and x must agree")
+	}
+	if len(space) == 0 {
+		return nil, errors.New("the search space is empty")
+	}
+	names := make([]string, 0, len(space))
+	for name, dim := range space {
+		if len(dim.values) == 0 {
+			return nil, fmt.Errorf("parameter %v has no candidate values", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var candidates []Params
+	if cfg.Samples > 0 {
+		candidates = randomCandidates(names, space, cfg.Samples, cfg.Seed)
+	} else {
+		candidates = gridCandidates(names, space)
+	}
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var mx sync.Mutex
+	inputs := make(map[int][][]float64)
+	inputMatrix := func(n int) [][]float64 {
+		mx.Lock()
+		defer mx.Unlock()
+		if _, ok := inputs[n]; !ok {
+			inputs[n] = delayMatrix(x, n)
+		}
+		return inputs[n]
+	}
+
+	results := make([]TuneResult, len(candidates))
+	err := adfutil.ParallelFor(ctx, len(candidates), workers, func(ctx context.Context, i int) error {
+		results[i] = TuneResult{Params: candidates[i], Score: math.Inf(1)}
+		af, err := newFilter(candidates[i])
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+		n, _, _ := af.GetParams()
+		res, err := FitContext(ctx, af, d, inputMatrix(n), cfg.FitConfig)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+		results[i].Score = res.BestError
+		results[i].Fit = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Score < results[j].Score
+	})
+	return results, nil
+}
+
+//gridCandidates returns every combination of the grid values of `space`.
+func gridCandidates(names []string, space SearchSpace) []Params {
+	candidates := []Params{{}}
+	for _, name := range names {
+		next := make([]Params, 0, len(candidates)*len(space[name].values))
+		for _, c := range candidates {
+			for _, v := range space[name].values {
+				p := make(Params, len(names))
+				for k, cv := range c {
+					p[k] = cv
+				}
+				p[name] = v
+				next = append(next, p)
+			}
+		}
+		candidates = next
+	}
+	return candidates
+}
+
+//randomCandidates returns `samples` candidates drawn randomly from `space`.
+func randomCandidates(names []string, space SearchSpace, samples int, seed int64) []Params {
+	r := rand.New(rand.NewSource(seed))
+	candidates := make([]Params, samples)
+	for i := range candidates {
+		p := make(Params, len(names))
+		for _, name := range names {
+			p[name] = space[name].sample(r)
+		}
+		candidates[i] = p
+	}
+	return candidates
+}
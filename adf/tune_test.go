@@ -0,0 +1,125 @@
+package adf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+//newSystemSignal makes the input signal `x` and the desired signal `d` of an unknown FIR system `h` with noise,
+//that is the newest samples of the rows of newSystemData.
+func newSystemSignal(n int, h []float64, noise float64) (d, x []float64) {
+	d, xMat := newSystemData(n, h, noise)
+	x = make([]float64, n)
+	for i := range xMat {
+		x[i] = xMat[i][len(h)-1]
+	}
+	return d, x
+}
+
+func TestTune(t *testing.T) {
+	rand.Seed(1)
+	//unknown system of length 4
+	d, x := newSystemSignal(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+	newNLMS := func(p Params) (AdaptiveFilter, error) {
+		return NewFiltNLMS(p.Int("n"), p["mu"], p["eps"], nil)
+	}
+	space := SearchSpace{
+		"n":   Discrete(1, 2, 4),
+		"mu":  Log(0.01, 1, 5),
+		"eps": Discrete(1e-5, 2),
+	}
+	cfg := TuneConfig{FitConfig: FitConfig{NTrain: 0.5, Epochs: 3, Criteria: "MSE"}, Workers: 4}
+
+	results, err := Tune(newNLMS, d, x, space, cfg)
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	if len(results) != 3*5*2 {
+		t.Fatalf("Tune() len(results) = %d, want %d", len(results), 3*5*2)
+	}
+	//eps = 2 is out of the range of NLMS
+	for i, r := range results {
+		if (r.Err != nil) != (r.Params["eps"] == 2) {
+			t.Errorf("Tune() results[%d] = %v, Err = %v", i, r.Params, r.Err)
+		}
+		if i > 0 && r.Err == nil && r.Score < results[i-1].Score {
+			t.Errorf("Tune() results are not sorted by score: %v < %v", r.Score, results[i-1].Score)
+		}
+	}
+	if best := results[0].Params; best.Int("n") != 4 {
+		t.Errorf("Tune() best params = %v, want n = 4", best)
+	}
+
+	//random search
+	cfg.Samples = 8
+	cfg.Seed = 1
+	random, err := Tune(newNLMS, d, x, space, cfg)
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	if len(random) != 8 {
+		t.Fatalf("Tune() len(results) = %d, want 8", len(random))
+	}
+	for _, r := range random {
+		if mu := r.Params["mu"]; mu < 0.01 || 1 < mu {
+			t.Errorf("Tune() sampled mu = %v, want in [0.01, 1]", mu)
+		}
+	}
+	again, err := Tune(newNLMS, d, x, space, cfg)
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	if !reflect.DeepEqual(again[0].Params, random[0].Params) {
+		t.Errorf("Tune() with the same seed = %v, want %v", again[0].Params, random[0].Params)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := TuneContext(ctx, newNLMS, d, x, space, cfg); !errors.Is(err, context.Canceled) {
+		t.Errorf("TuneContext() with canceled context: error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLog(t *testing.T) {
+	got := Log(0.001, 10, 5).values
+	want := []float64{0.001, 0.01, 0.1, 1, 10}
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Log() = %v, want %v", got, want)
+	}
+}
+
+func TestDelayMatrix(t *testing.T) {
+	got := delayMatrix([]float64{1, 2, 3, 4}, 3)
+	want := [][]float64{{0, 0, 1}, {0, 1, 2}, {1, 2, 3}, {2, 3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("delayMatrix() = %v, want %v", got, want)
+	}
+}
+
+func ExampleTune() {
+	rand.Seed(1)
+	d, x := newSystemSignal(512, []float64{0.4, -0.2, 0.1, 0.05}, 0.01)
+
+	//tune the filter length, the projection order and the step size of AP filter
+	newAP := func(p Params) (AdaptiveFilter, error) {
+		return NewFiltAP(p.Int("n"), p["mu"], p.Int("order"), 1e-3, nil)
+	}
+	space := SearchSpace{
+		"n":     Discrete(2, 4, 8),
+		"order": Discrete(1, 2, 4),
+		"mu":    Lin(0.1, 1.0, 4),
+	}
+	cfg := TuneConfig{FitConfig: FitConfig{NTrain: 0.5, Epochs: 1, Criteria: "MSE"}}
+	results, err := Tune(newAP, d, x, space, cfg)
+	check(err)
+	best := results[0]
+	fmt.Printf("n: %d, order: %d, mu: %.1f\n", best.Params.Int("n"), best.Params.Int("order"), best.Params["mu"])
+	//output:
+	//n: 4, order: 1, mu: 0.4
+}
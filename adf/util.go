@@ -1,6 +1,9 @@
 package adf
 
-import "log"
+import (
+	"log"
+	"math"
+)
 
 func check(err error) {
 	if err != nil {
@@ -8,3 +11,33 @@ func check(err error) {
 	}
 }
 
+//delayMatrix makes the input matrix of a filter of length `n` from the input signal `x`.
+//Each row holds the latest `n` samples, oldest first, and the samples before the signal are zeros.
+func delayMatrix(x []float64, n int) [][]float64 {
+	xMat := make([][]float64, len(x))
+	for i := range x {
+		xMat[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			if k := i - n + 1 + j; k >= 0 {
+				xMat[i][j] = x[k]
+			}
+		}
+	}
+	return xMat
+}
+
+//sign returns the sign of `v`, that is -1, 0 or 1.
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+//clip limits `v` to the range [low, high].
+func clip(v, low, high float64) float64 {
+	return math.Max(low, math.Min(high, v))
+}
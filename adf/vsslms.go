@@ -0,0 +1,271 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the variable step-size LMS filters.
+const (
+	kindVSSLMS        = "VSS-LMS filter"
+	kindMathewsLMS    = "Mathews VSS-LMS filter"
+	kindBenvenisteLMS = "Benveniste VSS-LMS filter"
+)
+
+//vssBase is base struct of the variable step-size LMS filters.
+//The current step size is `mu` of filtBase and it is kept in the range [muMin, muMax].
+type vssBase struct {
+	filtBase
+	//mu0 is the initial step size restored by Reset.
+	mu0 float64
+}
+
+//initVSS initializes the common part of the variable step-size LMS filters.
+//The range [muMin, muMax] must be a part of the stable range of LMS [0, 2] and include `mu`.
+func (af *vssBase) initVSS(kind string, n int, mu, muMin, muMax float64, w []float64) error {
+	var err error
+	af.kind = kind
+	af.n = n
+	af.muMin, err = af.checkFloatParam(muMin, 0, 2, "muMin")
+	if err != nil {
+		return err
+	}
+	af.muMax, err = af.checkFloatParam(muMax, muMin, 2, "muMax")
+	if err != nil {
+		return err
+	}
+	af.mu, err = af.checkFloatParam(mu, af.muMin, af.muMax, "mu")
+	if err != nil {
+		return err
+	}
+	af.mu0 = af.mu
+	return af.initWeights(w, n)
+}
+
+//SetStepSize sets the current step size mu.
+//It is also the initial step size restored by Reset.
+func (af *vssBase) SetStepSize(mu float64) error {
+	if err := af.filtBase.SetStepSize(mu); err != nil {
+		return err
+	}
+	af.mu0 = af.mu
+	return nil
+}
+
+//Reset sets the filter weights to zeros and the step size to the initial value.
+func (af *vssBase) Reset() {
+	af.filtBase.Reset()
+	af.mu = af.mu0
+}
+
+//FiltVSSLMS is base struct for variable step-size LMS filter of Kwong and Johnston.
+//The step size follows the power of the error:
+//mu <- clip(alpha*mu + gamma*e^2, muMin, muMax).
+//The current step size is returned by GetParams.
+//Use NewFiltVSSLMS to make instance.
+type FiltVSSLMS struct {
+	vssBase
+	alpha float64
+	gamma float64
+}
+
+//NewFiltVSSLMS is constructor of variable step-size LMS filter of Kwong and Johnston.
+//This func initialize filter length `n`, initial step size `mu`, the range of the step size [`muMin`, `muMax`],
+//forgetting factor of the step size `alpha`, the gain of the squared error `gamma` and filter weight `w`.
+//Typical values are `alpha` = 0.97 and a small `gamma` such as 1e-3.
+func NewFiltVSSLMS(n int, mu, muMin, muMax, alpha, gamma float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltVSSLMS)
+	if err = p.initVSS(kindVSSLMS, n, mu, muMin, muMax, w); err != nil {
+		return nil, err
+	}
+	p.alpha, err = p.checkFloatParam(alpha, 0, 1, "alpha")
+	if err != nil {
+		return nil, err
+	}
+	p.gamma, err = p.checkFloatParam(gamma, 0, 1, "gamma")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights and the step size according to error `e`.
+func (af *FiltVSSLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights and the step size according to error `e`.
+func (af *FiltVSSLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	floats.AddScaled(w, af.mu*e, x)
+	af.mu = clip(af.alpha*af.mu+af.gamma*e*e, af.muMin, af.muMax)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights and the step size according to error `e`.
+//Use WithStepSizeHistory to get the step size of every sample.
+func (af *FiltVSSLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltVSSLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltMathewsLMS is base struct for gradient adaptive step-size LMS filter of Mathews and Xie.
+//The step size follows the correlation of the successive gradients:
+//mu <- clip(mu + rho*e(k)*e(k-1)*x(k-1)·x(k), muMin, muMax).
+//The current step size is returned by GetParams.
+//Use NewFiltMathewsLMS to make instance.
+type FiltMathewsLMS struct {
+	vssBase
+	rho   float64
+	ePrev float64
+	xPrev []float64
+}
+
+//NewFiltMathewsLMS is constructor of gradient adaptive step-size LMS filter of Mathews and Xie.
+//This func initialize filter length `n`, initial step size `mu`, the range of the step size [`muMin`, `muMax`],
+//the learning rate of the step size `rho` and filter weight `w`.
+func NewFiltMathewsLMS(n int, mu, muMin, muMax, rho float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltMathewsLMS)
+	if err = p.initVSS(kindMathewsLMS, n, mu, muMin, muMax, w); err != nil {
+		return nil, err
+	}
+	p.rho, err = p.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return nil, err
+	}
+	p.xPrev = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update the step size and filter weights according to error `e`.
+func (af *FiltMathewsLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update the step size and filter weights according to error `e`.
+func (af *FiltMathewsLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	af.mu = clip(af.mu+af.rho*e*af.ePrev*floats.Dot(af.xPrev, x), af.muMin, af.muMax)
+	floats.AddScaled(w, af.mu*e, x)
+	af.ePrev = e
+	copy(af.xPrev, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating the step size and filter weights according to error `e`.
+//Use WithStepSizeHistory to get the step size of every sample.
+func (af *FiltMathewsLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Reset sets the filter weights and the previous error and input to zeros, and the step size to the initial value.
+func (af *FiltMathewsLMS) Reset() {
+	af.vssBase.Reset()
+	af.ePrev = 0
+	af.xPrev = make([]float64, af.n)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltMathewsLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.xPrev = append([]float64{}, af.xPrev...)
+	return &altaf
+}
+
+//FiltBenvenisteLMS is base struct for gradient adaptive step-size LMS filter of Benveniste.
+//The step size follows the gradient of the squared error with respect to the step size,
+//which is tracked by the vector psi:
+//psi <- (I - mu(k-1)*x(k-1)*x(k-1)^T)*psi + e(k-1)*x(k-1),
+//mu <- clip(mu + rho*e(k)*x(k)·psi, muMin, muMax).
+//The current step size is returned by GetParams.
+//Use NewFiltBenvenisteLMS to make instance.
+type FiltBenvenisteLMS struct {
+	vssBase
+	rho   float64
+	ePrev float64
+	xPrev []float64
+	psi   []float64
+}
+
+//NewFiltBenvenisteLMS is constructor of gradient adaptive step-size LMS filter of Benveniste.
+//This func initialize filter length `n`, initial step size `mu`, the range of the step size [`muMin`, `muMax`],
+//the learning rate of the step size `rho` and filter weight `w`.
+func NewFiltBenvenisteLMS(n int, mu, muMin, muMax, rho float64, w []float64) (AdaptiveFilter, error) {
+	var err error
+	p := new(FiltBenvenisteLMS)
+	if err = p.initVSS(kindBenvenisteLMS, n, mu, muMin, muMax, w); err != nil {
+		return nil, err
+	}
+	p.rho, err = p.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return nil, err
+	}
+	p.xPrev = make([]float64, n)
+	p.psi = make([]float64, n)
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update the step size and filter weights according to error `e`.
+func (af *FiltBenvenisteLMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update the step size and filter weights according to error `e`.
+func (af *FiltBenvenisteLMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	//the step size is still mu(k-1) here
+	floats.AddScaled(af.psi, -af.mu*floats.Dot(af.xPrev, af.psi), af.xPrev)
+	floats.AddScaled(af.psi, af.ePrev, af.xPrev)
+	af.mu = clip(af.mu+af.rho*e*floats.Dot(x, af.psi), af.muMin, af.muMax)
+	floats.AddScaled(w, af.mu*e, x)
+	af.ePrev = e
+	copy(af.xPrev, x)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating the step size and filter weights according to error `e`.
+//Use WithStepSizeHistory to get the step size of every sample.
+func (af *FiltBenvenisteLMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Reset sets the filter weights, psi and the previous error and input to zeros, and the step size to the initial value.
+func (af *FiltBenvenisteLMS) Reset() {
+	af.vssBase.Reset()
+	af.ePrev = 0
+	af.xPrev = make([]float64, af.n)
+	af.psi = make([]float64, af.n)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltBenvenisteLMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.xPrev = append([]float64{}, af.xPrev...)
+	altaf.psi = append([]float64{}, af.psi...)
+	return &altaf
+}
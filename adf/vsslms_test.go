@@ -0,0 +1,72 @@
+package adf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestVSSLMS_Run(t *testing.T) {
+	rand.Seed(1)
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(4096, h, 0.1)
+	const muMin, muMax = 0.001, 0.1
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "Kwong-Johnston", af: Must(NewFiltVSSLMS(len(h), 0.01, muMin, muMax, 0.97, 1e-3, nil))},
+		{name: "Mathews", af: Must(NewFiltMathewsLMS(len(h), 0.01, muMin, muMax, 1e-3, nil))},
+		{name: "Benveniste", af: Must(NewFiltBenvenisteLMS(len(h), 0.01, muMin, muMax, 1e-3, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mus []float64
+			_, _, _, err := tt.af.Run(d, x, WithStepSizeHistory(&mus))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(mus) != len(d) {
+				t.Fatalf("Run() len(mu history) = %d, want %d", len(mus), len(d))
+			}
+			if min, max := floats.Min(mus), floats.Max(mus); min < muMin || muMax < max {
+				t.Errorf("Run() mu history in [%v, %v], want in [%v, %v]", min, max, muMin, muMax)
+			}
+			_, mu, w := tt.af.GetParams()
+			if mu != mus[len(mus)-1] {
+				t.Errorf("GetParams() mu = %v, want the last step size %v", mu, mus[len(mus)-1])
+			}
+			//the step size is large while converging and small in the steady state
+			if early, late := floats.Max(mus[:256]), floats.Sum(mus[len(mus)-256:])/256; early <= late {
+				t.Errorf("Run() mu early = %v, mu late = %v, want early > late", early, late)
+			}
+			if !floats.EqualApprox(w, h, 0.05) {
+				t.Errorf("Run() w = %v, want %v", w, h)
+			}
+			tt.af.Reset()
+			if _, mu, _ := tt.af.GetParams(); mu != 0.01 {
+				t.Errorf("Reset() mu = %v, want the initial step size %v", mu, 0.01)
+			}
+		})
+	}
+}
+
+func ExampleFiltVSSLMS_Run() {
+	rand.Seed(1)
+	//unknown system
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	d, x := newSystemData(2048, h, 0.1)
+
+	af := Must(NewFiltVSSLMS(len(h), 0.1, 0.001, 0.1, 0.97, 1e-3, nil))
+	var mus []float64
+	_, _, _, err := af.Run(d, x, WithoutHistory(), WithStepSizeHistory(&mus))
+	check(err)
+	_, mu, w := af.GetParams()
+	fmt.Printf("mu: %.4f -> %.4f\n", mus[0], mu)
+	fmt.Printf("w: %.2f\n", w)
+	//output:
+	//mu: 0.0970 -> 0.0010
+	//w: [0.39 -0.20 0.10 0.05]
+}
@@ -0,0 +1,201 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+//kind names of the zero-attracting LMS filters.
+const (
+	kindZALMS  = "ZA-LMS filter"
+	kindRZALMS = "RZA-LMS filter"
+	kindL0LMS  = "l0-LMS filter"
+)
+
+//newZALMSBase initializes the common part of the zero-attracting LMS filters.
+//`mu` is checked in the same way as NewFiltLMS, and the attraction strength `rho` in the range [0, 1].
+func newZALMSBase(kind string, n int, mu, rho float64, w []float64) (filtBase, float64, error) {
+	base, err := newLMSBase(kind, n, mu, w)
+	if err != nil {
+		return base, 0, err
+	}
+	rho, err = base.checkFloatParam(rho, 0, 1, "rho")
+	if err != nil {
+		return base, 0, err
+	}
+	return base, rho, nil
+}
+
+//FiltZALMS is base struct for ZA-LMS filter (Zero-Attracting LMS filter).
+//The l1 norm penalty attracts every weight to zero by a constant amount:
+//w += mu * e * x - rho * sign(w).
+//Use NewFiltZALMS to make instance.
+type FiltZALMS struct {
+	filtBase
+	rho float64
+}
+
+//NewFiltZALMS is constructor of ZA-LMS filter.
+//This func initialize filter length `n`, update step size `mu`, the attraction strength `rho` and filter weight `w`.
+//`rho` is typically much smaller than `mu`, such as 1e-4.
+func NewFiltZALMS(n int, mu, rho float64, w []float64) (AdaptiveFilter, error) {
+	base, rho, err := newZALMSBase(kindZALMS, n, mu, rho, w)
+	if err != nil {
+		return nil, err
+	}
+	return &FiltZALMS{filtBase: base, rho: rho}, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltZALMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltZALMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	for i := 0; i < len(x); i++ {
+		w[i] += af.mu*e*x[i] - af.rho*sign(w[i])
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` and the attraction to zero.
+func (af *FiltZALMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltZALMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltRZALMS is base struct for RZA-LMS filter (Reweighted Zero-Attracting LMS filter).
+//The attraction is weakened for the large weights by the shape parameter `eps`:
+//w += mu * e * x - rho * sign(w) / (1 + eps*|w|).
+//Use NewFiltRZALMS to make instance.
+type FiltRZALMS struct {
+	filtBase
+	rho float64
+	eps float64
+}
+
+//NewFiltRZALMS is constructor of RZA-LMS filter.
+//This func initialize filter length `n`, update step size `mu`, the attraction strength `rho`,
+//the shape parameter `eps` and filter weight `w`.
+//`eps` is about the inverse of the smallest magnitude of the active weights, such as 10.
+func NewFiltRZALMS(n int, mu, rho, eps float64, w []float64) (AdaptiveFilter, error) {
+	base, rho, err := newZALMSBase(kindRZALMS, n, mu, rho, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltRZALMS{filtBase: base, rho: rho}
+	p.eps, err = p.checkFloatParam(eps, 0, math.MaxFloat64, "eps")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltRZALMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltRZALMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	for i := 0; i < len(x); i++ {
+		w[i] += af.mu*e*x[i] - af.rho*sign(w[i])/(1+af.eps*math.Abs(w[i]))
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` and the attraction to zero.
+func (af *FiltRZALMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltRZALMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
+
+//FiltL0LMS is base struct for l0-LMS filter.
+//The l0 norm is approximated with the shape parameter `beta`,
+//and only the weights smaller than 1/beta are attracted to zero:
+//w += mu * e * x - kappa * (beta*sign(w) - beta^2*w) for |w| <= 1/beta.
+//Use NewFiltL0LMS to make instance.
+type FiltL0LMS struct {
+	filtBase
+	kappa float64
+	beta  float64
+}
+
+//NewFiltL0LMS is constructor of l0-LMS filter.
+//This func initialize filter length `n`, update step size `mu`, the attraction strength `kappa`,
+//the shape parameter `beta` and filter weight `w`.
+//`beta` is about the inverse of the smallest magnitude of the active weights, such as 10.
+func NewFiltL0LMS(n int, mu, kappa, beta float64, w []float64) (AdaptiveFilter, error) {
+	base, kappa, err := newZALMSBase(kindL0LMS, n, mu, kappa, w)
+	if err != nil {
+		return nil, err
+	}
+	p := &FiltL0LMS{filtBase: base, kappa: kappa}
+	p.beta, err = p.checkFloatParam(beta, 0, math.MaxFloat64, "beta")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltL0LMS) Adapt(d float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the estimated value `y` and the error `e` between desired value `d` and `y`,
+//and update filter weights according to error `e` and the attraction to zero.
+func (af *FiltL0LMS) Step(d float64, x []float64) (y, e float64) {
+	w := af.w.RawRowView(0)
+	y = floats.Dot(w, x)
+	e = d - y
+	for i := 0; i < len(x); i++ {
+		var g float64
+		if math.Abs(w[i])*af.beta <= 1 {
+			g = af.beta*sign(w[i]) - af.beta*af.beta*w[i]
+		}
+		w[i] += af.mu*e*x[i] - af.kappa*g
+	}
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e` and the attraction to zero.
+func (af *FiltL0LMS) Run(d []float64, x [][]float64, opts ...RunOption) (y []float64, e []float64, wHist [][]float64, err error) {
+	return af.runSteps(d, x, opts, af.Step, nil)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltL0LMS) Clone() AdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
@@ -0,0 +1,83 @@
+package adf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestZeroAttractingLMS_sparse(t *testing.T) {
+	rand.Seed(1)
+	L := 64
+	h := newSparseSystem(L)
+	d, x := newSystemData(4000, h, 0.05)
+	const tol = 1e-3
+
+	//near-zero taps of LMS in the same scenario
+	lms := Must(NewFiltLMS(L, 0.01, nil))
+	_, _, _, err := lms.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, wLMS := lms.GetParams()
+	ref := misc.CountNearZero(wLMS, tol)
+
+	tests := []struct {
+		name string
+		af   AdaptiveFilter
+	}{
+		{name: "ZA-LMS", af: Must(NewFiltZALMS(L, 0.01, 5e-5, nil))},
+		{name: "RZA-LMS", af: Must(NewFiltRZALMS(L, 0.01, 1e-4, 10, nil))},
+		{name: "l0-LMS", af: Must(NewFiltL0LMS(L, 0.01, 1e-5, 10, nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := tt.af.Run(d, x, WithoutHistory())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			_, _, w := tt.af.GetParams()
+			got := misc.CountNearZero(w, tol)
+			if got <= ref {
+				t.Errorf("%s has %d near-zero taps, want more than LMS with %d", tt.name, got, ref)
+			}
+			if m := misalignment(w, h); m > misalignment(wLMS, h) {
+				t.Errorf("%s misalignment = %.1f dB, want lower than LMS %.1f dB", tt.name, m, misalignment(wLMS, h))
+			}
+		})
+	}
+}
+
+func TestZeroAttractingLMS_invalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "ZA-LMS mu", err: second(NewFiltZALMS(4, 3, 1e-4, nil))},
+		{name: "ZA-LMS rho", err: second(NewFiltZALMS(4, 0.01, -1, nil))},
+		{name: "RZA-LMS eps", err: second(NewFiltRZALMS(4, 0.01, 1e-4, -1, nil))},
+		{name: "l0-LMS beta", err: second(NewFiltL0LMS(4, 0.01, 1e-5, -1, nil))},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: error = nil, want error", tt.name)
+		}
+	}
+}
+
+func ExampleFiltRZALMS_Run() {
+	rand.Seed(1)
+	//sparse unknown system with 3 active taps of 64
+	h := newSparseSystem(64)
+	d, x := newSystemData(4000, h, 0.05)
+
+	af := Must(NewFiltRZALMS(len(h), 0.01, 1e-4, 10, nil))
+	_, _, _, err := af.Run(d, x, WithoutHistory())
+	check(err)
+	_, _, w := af.GetParams()
+	fmt.Printf("near-zero taps: %d of %d\n", misc.CountNearZero(w, 1e-3), len(w))
+	fmt.Printf("active taps: %.2f %.2f %.2f\n", w[5], w[12], w[30])
+	//output:
+	//near-zero taps: 32 of 64
+	//active taps: 0.99 -0.50 0.24
+}
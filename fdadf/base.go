@@ -1,10 +1,14 @@
 package fdadf
 
 import (
+	"context"
 	"fmt"
+	"runtime"
 
 	"github.com/pkg/errors"
+	"github.com/tetsuzawa/go-adflib/internal/adfutil"
 	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -19,9 +23,15 @@ type FDAdaptiveFilter interface {
 	//and update filter weights according to error `e`.
 	Adapt(d []float64, x []float64)
 
+	//Step calculates the output `y` and the error `e` between desired values `d` and `y` of the block,
+	//and update filter weights according to error `e`.
+	//It is Adapt which also returns `y` and `e` of the block.
+	Step(d []float64, x []float64) (y, e []float64)
+
 	//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 	//while updating filter weights according to error `e`.
-	Run(d [][]float64, x [][]float64) ([][]float64, [][]float64, [][]float64, error)
+	//The weight history is configured by `opts`.
+	Run(d [][]float64, x [][]float64, opts ...RunOption) ([][]float64, [][]float64, [][]float64, error)
 	checkFloatParam(p, low, high float64, name string) (float64, error)
 	checkIntParam(p, low, high int, name string) (int, error)
 	setStepSize(mu float64)
@@ -32,6 +42,10 @@ type FDAdaptiveFilter interface {
 
 	//GetParams returns the name of FDADF.
 	GetKindName() (kind string)
+
+	//Clone returns a deep copy of the filter.
+	//The copy can be adapted independently of the original.
+	Clone() FDAdaptiveFilter
 }
 
 //Must checks whether err is nil or not. If err in not nil, this func causes panic.
@@ -47,17 +61,26 @@ func Must(af FDAdaptiveFilter, err error) FDAdaptiveFilter {
 //`x` is input matrix. rows are samples and columns are features.
 //`nTrain` is train to test ratio, typical value is 0.5. (that means 50% of data is used for training).
 //`epochs` is number of training epochs, typical value is 1. This number describes how many times the training will be repeated.
-func PreTrainedRun(af FDAdaptiveFilter, d [][]float64, x [][]float64, nTrain float64, epochs int) (y, e [][]float64, w [][]float64, err error) {
+//`opts` configures the weight history of the final run. No history is recorded during the training.
+func PreTrainedRun(af FDAdaptiveFilter, d [][]float64, x [][]float64, nTrain float64, epochs int, opts ...RunOption) (y, e [][]float64, w [][]float64, err error) {
+	return preTrainedRun(context.Background(), af, d, x, nTrain, epochs, opts...)
+}
+
+//preTrainedRun is PreTrainedRun which stops between the epochs when `ctx` is done.
+func preTrainedRun(ctx context.Context, af FDAdaptiveFilter, d [][]float64, x [][]float64, nTrain float64, epochs int, opts ...RunOption) (y, e [][]float64, w [][]float64, err error) {
 	var nTrainI = int(float64(len(d)) * nTrain)
 	//train
 	for i := 0; i < epochs; i++ {
-		_, _, _, err = af.Run(d[:nTrainI], x[:nTrainI])
+		if err = ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		_, _, _, err = af.Run(d[:nTrainI], x[:nTrainI], WithoutHistory())
 		if err != nil {
 			return nil, nil, nil, err
 		}
 	}
 	//run
-	y, e, w, err = af.Run(d[:nTrainI], x[:nTrainI])
+	y, e, w, err = af.Run(d[:nTrainI], x[:nTrainI], opts...)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -65,6 +88,8 @@ func PreTrainedRun(af FDAdaptiveFilter, d [][]float64, x [][]float64, nTrain flo
 }
 
 //ExploreLearning searches the `mu` with the smallest error value from the input matrix `x` and desired values `d`.
+//Each `mu` is tested on an independent clone of `af`, so `af` itself is not changed.
+//The step sizes are tested in parallel on all available CPUs. See ExploreLearningContext.
 //The arg `d` is desired value.
 //`x` is input matrix.
 //`muStart` is starting learning rate.
@@ -74,39 +99,65 @@ func PreTrainedRun(af FDAdaptiveFilter, d [][]float64, x [][]float64, nTrain flo
 //`epochs` is number of training epochs, typical value is 1. This number describes how many times the training will be repeated.
 //`criteria` is how should be measured the mean error. Available values are "MSE", "MAE" and "RMSE".
 //`target_w` is target weights. If the slice is nil, the mean error is estimated from prediction error.
-// If an slice is provided, the error between weights and `target_w` is used.
+// If an slice is provided, the error between the first `n` weights and `target_w` is used.
 func ExploreLearning(af FDAdaptiveFilter, d [][]float64, x [][]float64, muStart, muEnd float64, steps int,
 	nTrain float64, epochs int, criteria string, targetW []float64) ([]float64, []float64, error) {
+	return ExploreLearningContext(context.Background(), af, d, x, muStart, muEnd, steps,
+		nTrain, epochs, criteria, targetW, runtime.GOMAXPROCS(0))
+}
+
+//ExploreLearningContext is ExploreLearning with cancellation and a bounded number of workers.
+//`workers` is the maximum number of step sizes tested at the same time. If it is smaller than 1, 1 is used.
+//When `ctx` is done, the remaining step sizes are not tested and the error of `ctx` is returned.
+func ExploreLearningContext(ctx context.Context, af FDAdaptiveFilter, d [][]float64, x [][]float64, muStart, muEnd float64, steps int,
+	nTrain float64, epochs int, criteria string, targetW []float64, workers int) ([]float64, []float64, error) {
 	mus := misc.LinSpace(muStart, muEnd, steps)
 	es := make([]float64, len(mus))
-	zeros := make([]float64, int(float64(len(x))*nTrain))
-	ee := make([]float64, int(float64(len(x))*nTrain))
-	_, _, w := af.GetParams()
-	for i, mu := range mus {
-		//init
-		err := af.initWeights("zeros", len(w))
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to init weights at InitWights()")
-		}
-		af.setStepSize(mu)
-		//run
-		_, e, _, err := PreTrainedRun(af, d, x, nTrain, epochs)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to pre train at PreTrainedRun()")
-		}
+	err := adfutil.ParallelFor(ctx, len(mus), workers, func(ctx context.Context, i int) error {
+		var err error
+		es[i], err = exploreStepSize(ctx, af, d, x, mus[i], nTrain, epochs, criteria, targetW)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return es, mus, nil
+}
+
+//exploreStepSize returns the mean error of a clone of `af` trained with the step size `mu`.
+func exploreStepSize(ctx context.Context, af FDAdaptiveFilter, d [][]float64, x [][]float64, mu float64,
+	nTrain float64, epochs int, criteria string, targetW []float64) (float64, error) {
+	//init
+	c := af.Clone()
+	n, _, w := c.GetParams()
+	err := c.initWeights("zeros", len(w))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to init weights at InitWights()")
+	}
+	c.setStepSize(mu)
+	//run
+	_, e, _, err := preTrainedRun(ctx, c, d, x, nTrain, epochs, WithoutHistory())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to pre train at PreTrainedRun()")
+	}
+	var es float64
+	if targetW == nil {
+		ee := make([]float64, len(e))
 		for i, sl := range e {
 			ee[i], err = misc.MSE(sl, make([]float64, len(sl)))
+			if err != nil {
+				return 0, errors.Wrap(err, "failed to find MSE of e at misc.MSE()")
+			}
 		}
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to find MSE of e at misc.MSE()")
-		}
-		es[i], err = misc.GetMeanError(ee, zeros, criteria)
-		//fmt.Println(es[i])
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to get mean error at GetMeanError()")
-		}
+		es, err = misc.GetMeanError(ee, make([]float64, len(ee)), criteria)
+	} else {
+		_, _, w = c.GetParams()
+		es, err = misc.GetMeanError(append([]float64{}, w[:n]...), targetW, criteria)
 	}
-	return es, mus, nil
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get mean error at GetMeanError()")
+	}
+	return es, nil
 }
 
 //filtBase is base struct for frequency domain adaptive filter structs
@@ -169,25 +220,64 @@ func (af *filtBase) initWeights(w interface{}, n int) error {
 }
 
 //Predict calculates the new output value `y` from input array `x`.
+//The base filter convolves the block `x` with the weights in the time domain,
+//regarding the samples before the block as zeros.
 func (af *filtBase) Predict(x []float64) (y []float64) {
-	//TODO
-	//y = floats.Dot(af.w.RawRowView(0), x)
-	//return y
-	copy(y, x)
-	return
+	w := af.w.RawRowView(0)
+	y = make([]float64, len(x))
+	for i := range x {
+		for j := 0; j < len(w) && j <= i; j++ {
+			y[i] += w[j] * x[i-j]
+		}
+	}
+	return y
 }
 
 //Adapt is just a method to satisfy the interface.
-//It is used by overriding.
-func (af *filtBase) Adapt(d []float64, x []float64) {
-	//TODO
+//It is used by overriding. The base filter keeps its weights fixed.
+func (af *filtBase) Adapt(d []float64, x []float64) {}
+
+//Step calculates the output `y` of the block `x` by Predict
+//and then adapts the filter by Adapt.
+//The error `e` is `d - y`.
+func (af *filtBase) Step(d []float64, x []float64) (y, e []float64) {
+	y = af.Predict(x)
+	e = make([]float64, len(y))
+	floats.SubTo(e, d, y)
+	af.Adapt(d, x)
+	return y, e
 }
 
-//Run is just a method to satisfy the interface.
+//Run calculates the output `y` and the error `e` of the blocks `x` by Step.
 //It is used by overriding.
-func (af *filtBase) Run(d [][]float64, x [][]float64) ([][]float64, [][]float64, [][]float64, error) {
-	//TODO
-	return nil, nil, nil, nil
+func (af *filtBase) Run(d [][]float64, x [][]float64, opts ...RunOption) ([][]float64, [][]float64, [][]float64, error) {
+	return af.runSteps(d, x, opts, af.Step)
+}
+
+//runSteps is the adaptation loop of Run for the filters which adapt a block with `step`.
+//`step` is the Step method of the filter.
+func (af *filtBase) runSteps(d [][]float64, x [][]float64, opts []RunOption,
+	step func(d, x []float64) (y, e []float64)) ([][]float64, [][]float64, [][]float64, error) {
+	//measure the data and check if the dimension agree
+	N := len(x)
+	if len(d) != N {
+		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+	}
+	if N > 0 && len(x[0]) != af.n {
+		return nil, nil, nil, fmt.Errorf("the length of rows of x and `n` must agree. len(x[0]): %d, n: %d", len(x[0]), af.n)
+	}
+	hist := newHistory(N, af.n, opts)
+
+	y := make([][]float64, N)
+	e := make([][]float64, N)
+	//adaptation loop
+	for k := 0; k < N; k++ {
+		if err := hist.Record(k, af.w.RawRowView(0)[:af.n]); err != nil {
+			return nil, nil, nil, err
+		}
+		y[k], e[k] = step(d[k], x[k])
+	}
+	return y, e, hist.Result(), nil
 }
 
 //checkFloatParam check if the value of the given parameter
@@ -227,3 +317,10 @@ func (af *filtBase) GetParams() (n int, mu float64, w []float64) {
 func (af *filtBase) GetKindName() (kind string) {
 	return af.kind
 }
+
+//Clone returns a deep copy of the filter.
+func (af *filtBase) Clone() FDAdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	return &altaf
+}
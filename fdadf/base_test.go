@@ -0,0 +1,48 @@
+package fdadf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestExploreLearningContext(t *testing.T) {
+	rand.Seed(1)
+	//number of blocks
+	m := 16
+	L := 8
+	//input value
+	var x = make([][]float64, m)
+	//desired value
+	var d = make([][]float64, m)
+	for i := 0; i < m; i++ {
+		x[i] = misc.NewNormRandSlice(L)
+		d[i] = append([]float64{}, x[i]...)
+	}
+	af := Must(NewFiltFBLMS(L, 0.01, "zeros"))
+
+	want, _, err := ExploreLearningContext(context.Background(), af, d, x, 0.001, 0.1, 8, 0.5, 2, "MSE", nil, 1)
+	if err != nil {
+		t.Fatalf("ExploreLearningContext() error = %v", err)
+	}
+	got, _, err := ExploreLearningContext(context.Background(), af, d, x, 0.001, 0.1, 8, 0.5, 2, "MSE", nil, 4)
+	if err != nil {
+		t.Fatalf("ExploreLearningContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExploreLearningContext() with 4 workers = %v, want %v", got, want)
+	}
+	if _, _, err := ExploreLearningContext(context.Background(), af, d, x, 0.001, 0.1, 8, 0.5, 2, "MSE", make([]float64, L), 4); err != nil {
+		t.Errorf("ExploreLearningContext() with targetW: error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := ExploreLearningContext(ctx, af, d, x, 0.001, 0.1, 8, 0.5, 2, "MSE", nil, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExploreLearningContext() with canceled context: error = %v, want %v", err, context.Canceled)
+	}
+}
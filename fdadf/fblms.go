@@ -1,6 +1,7 @@
 package fdadf
 
 import (
+	"encoding/json"
 	"math/cmplx"
 
 	"github.com/mjibson/go-dsp/fft"
@@ -8,13 +9,15 @@ import (
 	"gonum.org/v1/gonum/mat"
 )
 
+//kindFBLMS is the kind name of FiltFBLMS.
+const kindFBLMS = "FBLMS filter"
+
 //FiltFBLMS is base struct for FBLMS filter
 //(Fast Block Least Mean Square filter).
 //Use NewFiltFBLMS to make instance.
 type FiltFBLMS struct {
 	filtBase
-	wHistory [][]float64
-	xMem     *mat.Dense
+	xMem *mat.Dense
 }
 
 //NewFiltFBLMS is constructor of FBLMS filter.
@@ -22,7 +25,7 @@ type FiltFBLMS struct {
 func NewFiltFBLMS(n int, mu float64, w interface{}) (FDAdaptiveFilter, error) {
 	var err error
 	p := new(FiltFBLMS)
-	p.kind = "FBLMS filter"
+	p.kind = kindFBLMS
 	p.n = n
 	p.mu, err = p.checkFloatParam(mu, 0, 1000, "mu")
 	if err != nil {
@@ -39,10 +42,16 @@ func NewFiltFBLMS(n int, mu float64, w interface{}) (FDAdaptiveFilter, error) {
 //Adapt calculates the error `e` between desired value `d` and estimated value `y`,
 //and update filter weights according to error `e`.
 func (af *FiltFBLMS) Adapt(d []float64, x []float64) {
+	af.Step(d, x)
+}
+
+//Step calculates the output `y` and the error `e` between desired values `d` and `y` of the block,
+//and update filter weights according to error `e`.
+func (af *FiltFBLMS) Step(d []float64, x []float64) (y, e []float64) {
 	zeros := make([]float64, af.n)
 	Y := make([]complex128, 2*af.n)
-	y := make([]float64, af.n)
-	e := make([]float64, af.n)
+	y = make([]float64, af.n)
+	e = make([]float64, af.n)
 	EU := make([]complex128, 2*af.n)
 
 	w := af.w.RawRowView(0)
@@ -78,6 +87,7 @@ func (af *FiltFBLMS) Adapt(d []float64, x []float64) {
 	for i := 0; i < 2*af.n; i++ {
 		w[i] = real(aux3[i])
 	}
+	return y, e
 }
 
 //Predict calculates the new output value `y` from input array `x`.
@@ -100,69 +110,76 @@ func (af *FiltFBLMS) Predict(x []float64) (y []float64) {
 //Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
 //while updating filter weights according to error `e`.
 //The arg `x`: rows are samples sets, columns are input values.
-func (af *FiltFBLMS) Run(d [][]float64, x [][]float64) ([][]float64, [][]float64, [][]float64, error) {
-	//measure the data and check if the dimension agree
-	N := len(x)
-	if len(d) != N {
-		return nil, nil, nil, errors.New("the length of slice d and x must agree")
-	}
-	af.n = len(x[0])
-	af.wHistory = make([][]float64, N)
-	for i := range af.wHistory {
-		af.wHistory[i] = make([]float64, af.n)
+func (af *FiltFBLMS) Run(d [][]float64, x [][]float64, opts ...RunOption) ([][]float64, [][]float64, [][]float64, error) {
+	return af.runSteps(d, x, opts, af.Step)
+}
+
+//Clone returns a deep copy of the filter.
+func (af *FiltFBLMS) Clone() FDAdaptiveFilter {
+	altaf := *af
+	altaf.w = mat.DenseCopyOf(af.w)
+	altaf.xMem = mat.DenseCopyOf(af.xMem)
+	return &altaf
+}
+
+//state returns the complete state of the filter.
+func (af *FiltFBLMS) state() *filterState {
+	return &filterState{
+		Version: stateVersion,
+		Kind:    kindFBLMS,
+		N:       af.n,
+		Mu:      af.mu,
+		W:       append([]float64{}, af.w.RawRowView(0)...),
+		XMem:    append([]float64{}, af.xMem.RawRowView(0)...),
 	}
+}
 
-	zeros := make([]float64, af.n)
-	Y := make([]complex128, 2*af.n)
-	y := make([][]float64, N)
-	for i := range y {
-		y[i] = make([]float64, af.n)
+//setState restores the filter from the state `s`.
+func (af *FiltFBLMS) setState(s *filterState) error {
+	if err := checkState(s, kindFBLMS); err != nil {
+		return err
+	}
+	if err := checkStateLen(s.W, 2*s.N, "w"); err != nil {
+		return err
 	}
-	e := make([][]float64, N)
-	for i := range e {
-		e[i] = make([]float64, af.n)
+	if err := checkStateLen(s.XMem, s.N, "xMem"); err != nil {
+		return err
 	}
+	p, err := NewFiltFBLMS(s.N, s.Mu, s.W)
+	if err != nil {
+		return err
+	}
+	*af = *p.(*FiltFBLMS)
+	af.xMem = mat.NewDense(1, s.N, s.XMem)
+	return nil
+}
 
-	EU := make([]complex128, 2*af.n)
+//MarshalBinary implements encoding.BinaryMarshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltFBLMS) MarshalBinary() ([]byte, error) {
+	return encodeState(af.state())
+}
 
-	for k := 0; k < N; k++ {
-		w := af.w.RawRowView(0)
-		copy(af.wHistory[k], w)
-
-		// 1 compute the output of the filter for the block kM, ..., KM + M -1
-		W := fft.FFT(float64sToComplex128s(append(w[:af.n], zeros...)))
-		xSet := append(af.xMem.RawRowView(0), x[k]...)
-		U := fft.FFT(float64sToComplex128s(xSet))
-		af.xMem.SetRow(0, x[k])
-
-		for i := 0; i < 2*af.n; i++ {
-			Y[i] = W[i] * U[i]
-		}
-		yc := fft.IFFT(Y)[af.n:]
-		for i := 0; i < af.n; i++ {
-			y[k][i] = real(yc[i])
-			e[k][i] = x[k][i] - y[k][i]
-		}
-
-		// 2 compute the correlation vector
-		aux1 := fft.FFT(float64sToComplex128s(append(zeros, e[k]...)))
-		aux2 := fft.FFT(float64sToComplex128s(xSet))
-		for i := 0; i < 2*af.n; i++ {
-			EU[i] = aux1[i] * cmplx.Conj(aux2[i])
-		}
-		phi := fft.IFFT(EU)[:af.n]
-
-		// 3 update the parameters of the filter
-		aux1 = fft.FFT(float64sToComplex128s(append(w[:af.n], zeros...)))
-		aux2 = fft.FFT(append(phi, float64sToComplex128s(zeros)...))
-		for i := 0; i < 2*af.n; i++ {
-			W[i] = aux1[i] + complex(af.mu, 0)*aux2[i]
-		}
-		aux3 := fft.IFFT(W)
-		for i := 0; i < 2*af.n; i++ {
-			w[i] = real(aux3[i])
-		}
-	}
-
-	return y, e, af.wHistory, nil
+//UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (af *FiltFBLMS) UnmarshalBinary(data []byte) error {
+	s, err := decodeState(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
+}
+
+//MarshalJSON implements json.Marshaler.
+//The encoded state contains everything needed to resume the adaptation.
+func (af *FiltFBLMS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(af.state())
+}
+
+//UnmarshalJSON implements json.Unmarshaler.
+func (af *FiltFBLMS) UnmarshalJSON(data []byte) error {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return err
+	}
+	return af.setState(s)
 }
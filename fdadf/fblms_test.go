@@ -28,7 +28,7 @@ func TestFiltFBLMS_Run(t *testing.T) {
 			xRow = append(xRow, rand.NormFloat64())
 		}
 		x[i] = append([]float64{}, xRow...)
-		copy(d[i], x[i])
+		d[i] = floats.ScaleTo(make([]float64, L), 0.5, x[i])
 	}
 	type fields struct {
 		n  int
@@ -59,9 +59,9 @@ func TestFiltFBLMS_Run(t *testing.T) {
 				d: d,
 				x: x,
 			},
-			want:    [][]float64{{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,}, {0.46007738165372203, -1.3803186425829086, -1.6881399569621849, -0.971590983452151, 0.7289706035243824, -0.4496201280365677, -1.5337818629990616, -0.2845843339914286, 0.564110159782512, 0.9835841699026178, -1.0032580655193672, 1.126796775471001, 0.7488500574253548, -1.2477713303539755, -0.2247454556492881, 0.9854744022310382, 1.1292955500063875, -0.26930473883800116, 0.4757588488555911, 0.671887178485945, 0.1827553601638851, -0.2993973507867862, -0.6913713304328268, 1.121677878714811, 0.6833316224750116, -0.25700026873473736, -0.7020631381852391, -0.16153079314623886, -0.8150706409232982, 0.08163686809212067, 0.9918041749704984, 0.0536721300062693,}, {-0.5499901530432605, 0.7448342327382161, -0.9387184087289965, -0.07603112862750572, 2.319040748678003, -0.2312316027934307, -0.6449562527217527, 1.0135716490197142, -0.5929506409074166, -0.5745089379609811, -0.3009382797449096, -1.5374329849948984, -1.8672021428493824, -0.34706416144471974, 1.4740706983752336, -0.3398872207448844, 0.31875266874322983, -0.2792869646180906, 0.7469266826223715, -0.4855484570323629, -1.2367940830224171, -0.48028316536253945, -0.6648084754197431, -1.9654757393061013, 0.5106293689271886, 0.4018504850341231, -1.1686851611705271, 0.12264560499496813, 0.5014610704206875, -0.6645284229821413, -0.025867780482950353, 0.6748901077899601,}, {2.4510645636773365, -0.046065414128246884, -0.861808271260569, -2.172248221607376, -0.7924943828125537, -0.8530825011536854, 0.22592462931712387, -1.5267059799160305, 1.8262697318582335, 1.9542599137878063, 0.5538735163615441, -0.6378408575472845, 0.5426983642192758, 0.6666565259744025, -1.0601106347403184, -2.7067628091435783, -0.22658579044524707, -0.6642859182455494, 2.8128593140422375, -2.1847014965642355, 0.20011033343908935, -0.35976155239930563, 0.8711053278754973, -1.1537813601260087, 0.6517318180688395, 0.8571761466924224, -1.0468010556265837, -1.0685742308647712, -0.9851160010276296, 0.46234593174875394, -2.2430676101644766, -0.3438743544775209,}, {-1.4096845203458517, 1.446371177860563, -1.1840341183693153, 0.6860062729458136, 0.9838079411324234, -0.44666564966574973, 0.4933127341655348, -1.2199906495484776, 1.7277340197076594, 0.03604729544114471, -1.5792048344243028, 0.31743392701530004, -1.513814332014998, -2.156617536513305, 1.1036183586173176, -0.41596394476843956, -0.8075285984521188, 0.049162320260414255, -0.902260169846409, 1.8799079565641355, 0.3344212206274579, 0.18347905312431062, -1.3173870731502568, -2.133617387285965, 0.2979616694545996, -2.4204512642025633, 0.9186126097620018, 0.48698161119663114, 0.26491222223711575, 1.0108283074467983, 0.9846005635810088, -0.10059755932982611,}, {-0.8484752062340359, -0.37041671352276767, -1.5226718882815493, -1.4665550041934856, -0.8331696878652992, -1.5366248587326234, -1.615474338607684, -1.638011245691183, -2.6938109881799255, -0.2787887412947641, -0.15877945172183106, 0.234065612400746, 1.482655760032729, -0.6184927849310131, 0.9912263798065973, 1.796844780477398, 0.8860873812694062, 0.5426587044623222, -1.0798130291847174, 1.352210499915377, -1.432760909732333, 0.4441852356474151, 0.8988133085784344, -1.3319249625756053, -1.2109816357212109, 0.38132601887875195, 0.07742531792055825, 0.7736685802420635, 1.175308339566438, -1.013951355980275, 0.36564423922283285, 0.2476594617621788,}, {-0.5298134533318059, 0.8911128433698643, -0.37397385476447476, 0.013943785759745086, 1.2710614398110296, 0.3275632618222116, -0.04710678581074346, 0.44701234826778946, -0.5138489251267417, 0.7475711689550613, 3.4027983120281124, 0.6929874228182734, 0.1005900331594044, -0.44057764681114564, 1.270917910503059, -0.23910221463435044, -0.13927880748254173, 0.6237327436495589, -0.021484205451519522, -1.30486165796995, 2.000002218633512, 0.6252377870354157, -0.6577070245428551, 0.43973235723081117, -0.548348677559299, -0.02961822283728685, -1.0059000139216225, -1.3918166819275726, -2.4636015076226965, 0.33004968505385646, -0.8957272650670562, -0.0839542632647518,}, {0.10897417094343356, -0.6413052105098935, -0.1969020184720565, 0.3832091292818139, -0.41736987578747287, 0.2490451053215524, -0.740028681703127, -1.1282562021083806, -0.3710503256221299, -0.7604691630220975, 0.387971535761896, 0.24963278582228587, -0.4748707250390405, 0.19965872848900795, -0.017517897500725854, -1.0453028906649646, -0.020698273539066586, -0.36883466157000006, 0.657993527938384, -1.0776074896480188, -0.12190478920676695, 1.713479936471884, 1.3367078571131115, 0.03398479139146976, -0.7523475214859705, 0.7552979073993027, -0.591057614973126, 1.399656408467234, -0.3576602727366337, 1.1206052443238792, -0.5404197874081357, -0.08867825456669728,}, {0.21778310783735516, -0.7090234553251921, 1.3082968906895114, 0.04074497222401796, 1.6651384993039233, -0.4303932003372316, -0.630607543443691, 0.10329775923580886, -0.6089350283641091, 1.2074512104423345, -0.016437087916263526, 0.18556910532409132, 0.9127691608149487, -0.3925480817494301, 0.4964849921346325, 0.0004987111092755736, -0.25213655320810885, 1.836255733483374, 0.20401614776220922, -1.4255675510180974, -0.37065034994704465, -0.4784683940359432, 1.300571009706367, 1.0582735991883352, 0.6570103450022742, -0.35660354680066125, 2.1790118321768004, -1.6442738094264668, -2.124234569677557, 0.5977349093901076, 0.4150695141267504, -0.06610582906719333,}, {0.17822545120234543, 0.7698002169659924, 0.5997285263080312, 1.285637792532046, 1.1556690285562023, 1.5739119342872039, -0.09726027990202143, -1.9688810653713809, 0.7448618122775705, -0.09747260853561601, 0.25770157112143277, -0.19773911159436083, 0.7898520674459838, -0.005083451182024551, -0.4214581692825508, 0.012537552973089769, -0.6151279038654209, 0.6921161293084901, -0.20251551187174954, -0.8212182758468438, -0.707207636435323, 0.494696101059573, 1.187388422616855, -0.11779112936710112, -0.20793316278585053, 1.8975660501162728, -0.013931300214642395, 0.4533242086974083, 0.6193347066812224, -0.08042727967740365, -0.6439023662794845, -1.6030425714453016,}, {-1.0896237288975472, -0.4106462286434671, 0.9325466901515111, -2.136079368102709, 1.7226372463386226, -0.8117974941470083, -0.3380599927042028, -1.667593462981228, -1.9424646146131987, 1.2572492066062169, 0.06811348660987782, 0.33706213869172824, -0.2188317694168217, 1.1990170956647521, 1.115427231372864, 0.7940503312719462, 0.3896024948435245, -0.37358380478451825, 0.029400429761084146, 0.8562057509421708, 0.6283210321904871, -0.4239023945031694, 0.8253252308581125, 0.019815390468183952, -0.01848050563892814, 0.14744955096694123, -1.2293489475459634, 0.7063428545409638, -0.46756637847532845, -1.0949546053800878, 0.7281452428721618, 1.225270660852686,}, {0.6265677500237772, -0.9172682958142897, -0.004455669246157645, -0.4160935992111151, 0.6272247550930622, 0.137561040855823, -0.06764107593958335, -0.3762918099258543, -0.10939644493351697, 0.16196645426494682, -0.5220450128749208, 0.13697867581197798, 0.9376629932247391, 0.9131645745672661, 0.84573215296851, 0.10039590688963562, -1.3718798532930494, 0.9482875741491135, -0.31085064302606447, -0.079882947663582, -0.18351028348664578, 0.5684031449385343, -1.509179750644894, -0.9496115325668645, 0.99295837301895, 1.3562250224087156, 1.386985460657734, -0.04073637833910032, 1.8127456295244353, 1.422127049899909, 0.10288632657298313, -0.7275495303487438,}, {-0.37655239769658183, -0.9029149192577381, 1.2093851161758409, -0.9948082028806122, 0.8248986227926337, -0.2832247880234969, 0.3562371904716743, -1.8660970721707253, -0.5977445140169972, 0.757682187565168, 1.7186384364700824, -0.5815422108397224, 0.38936003855072215, -0.6910646607287416, -1.2051282228328226, 0.29836650245373597, 0.7325912322326334, 0.3252436903899042, -0.16005221767186137, -2.1481830532077018, 0.6702324206432655, -0.18174198814985854, -1.530748326608674, 1.110992951400131, -0.6270360210209155, -0.43819926943595044, -0.5655062578018817, 1.4094482275371958, -0.3219914729672494, -0.3136299439059973, 0.9153221864788319, 0.9993411799698229,}, {-0.5187796132627355, 1.5017317939111354, -0.7204960663426518, -0.7216830889571331, -1.3555106876417071, -1.4994096385038187, 1.1427947013894393, 1.6448971760383158, -1.427280460517803, 0.30900908219166084, 0.7878995144473504, -1.8303333257176881, 0.6304550661960654, -0.012552798304598434, -0.731871615082125, -1.0414165613951727, 1.25986017005819, -2.344055448172583, 0.19511555933807942, -1.3515673216478565, -1.0584388932754005, 1.4910742684470841, -0.6842276516946303, 0.1707323587672358, -0.693167961737225, -1.010654033132411, 0.2786987855889965, -1.9330419069579325, -0.07597885320009141, 1.380913556177671, -0.8683529964894854, -1.222872422446753,}, {-0.38591242568340806, -1.0243470214591222, 0.3015681936488799, 0.2308868592967442, 1.2238999038707021, 2.607904295998254, -0.7891924971508909, -0.9795271472806183, 1.165941060113219, -0.8224131092403097, 0.1335933075363197, 0.3418388540401269, -0.45805110450325465, 0.30799047411256997, 0.3438517653980138, 0.14906757361682194, -1.1312362388481707, 0.9310767511876658, 0.7936026129683742, 0.672312789526448, -0.23411903459463362, -1.0855830062081213, 0.9843700350342368, -0.11346374477066162, -0.9104988478478246, 0.6096156655796634, 0.0044542464576645124, 0.6785567660066041, -1.098962560403825, -1.221192093612835, -0.6079205582152125, 0.6480906601006078,}, {-0.9643531281233302, 2.5540214432147197, 0.8283564324524346, 0.8235108428190829, 0.7329760469883193, 0.035043592254051514, 0.31463567607288395, 0.022272255596710866, -0.3104296567823369, 1.3388747055092693, 0.8642517108573364, -0.8410372508852579, 0.9459042734808418, 1.1740955393189396, 0.25471951249501706, -0.6528116382378615, -1.3758596971073975, 0.2918740663783996, -0.3674282861272157, 0.8328211099212177, -0.8689399030228973, 0.5319037428557036, 1.069294341009088, -0.005949238210218963, -0.18885665261474427, 0.1487203706911287, 1.0146920043723653, 0.8939988169143684, 2.0458912945585945, 0.48330312673399134, 1.4114917655595554, -0.5902442218031965,},},
-			want1:   [][]float64{{-1.233758177597947, -0.12634751070237293, -0.5209945711531503, 2.28571911769958, 0.3228052526115799, 0.5900672875996937, 0.15880774017643562, 0.9892020842955818, -0.731283016177479, 0.6863807850359727, 1.585403962280623, 0.8382059044208106, 1.2988408475174342, 0.5273583930598617, 0.7324419258045132, -1.0731798210887524, 0.7001209024399848, 0.4315307186960532, 0.9996261210112625, -1.5239676725278932, -0.31653724289408824, 1.8894642062634817, 1.1007291937500208, -0.9927431907514367, 0.9897104202085316, -0.6152234852777649, -1.4350469221322282, -2.1514366827426445, 0.13735037357335078, 0.4428226270265666, -0.8460943734555971, -0.08279503413614919,}, {-0.3039508863709074, -0.07030460958756257, 1.9678836756253564, -0.7673154143563414, -0.026220341962201132, 0.7957590241914634, 0.4642364606460412, -0.5486675751983116, -0.23382077416985025, 0.7621575618753296, -0.11745602683726597, -0.3699979336243048, 0.1784484133387284, -0.2077344809983599, 1.1977798374924733, -1.2806880560564804, -0.6191794395203714, -0.20283206965786676, -0.2242188047964419, -0.7538246500195142, -0.01606464124558754, 0.6634424693173417, -0.9472697474236076, -0.2860095216888974, 0.47089919123502566, 0.18196392111994414, -0.06849780966455465, -0.9617623171129166, 0.0388574310495311, 0.5903069015845273, 0.42847532181838166, -0.42245059075363184,}, {0.2195333905682909, -0.8088683239643184, 0.6916836918987604, 0.24528208045859257, -0.5834074920234413, -0.03697003682148142, 0.4391223254648928, 0.1903867414133058, -0.45871465669652745, 0.07816154084837268, -0.37658526651822966, -0.3596096745020687, -0.09443576823552613, -0.3883803184248539, 0.5118485836058986, 0.4347063380998524, -0.3598907768122223, 0.10131468695361673, 0.4568049318640457, -0.5213405744285866, 0.398201320503067, 0.5817041452120392, -0.7382842529539221, -0.3609541158954337, 0.463653794961433, -0.1552443019648367, -0.8242772088209553, 0.49819576719349823, -0.12022733012073705, -0.3155762689978545, 0.43543897504240003, -0.1038079308434241,}, {0.07917387967507006, 0.417087483446565, -0.40843444723492417, -0.006562916977689959, 0.3718665701912302, -0.0044191725074663735, -0.22376396071448623, 0.3342324251357065, 0.5565906135737073, -0.47519645475077654, -0.3626073985609365, 0.6406240156717039, -0.5326921662344368, -0.7384690214927981, 0.6017977865340791, 0.5398388082915075, -0.24987178852094194, 0.10697675653522953, -0.08173744301779129, 0.7285421698762902, -0.14763969479181976, -0.25879855880688385, -0.07122892572193174, 0.24877234959265349, 0.07360566987487183, -0.4937245776385436, -0.09257482025546038, 0.44788706603025497, -0.030007679608548887, -0.31886743287871355, 0.1836258137738831, 0.2929631584807736,}, {0.10818307501413726, -0.13234737560712495, 0.001462194778679038, -0.05118037590553093, 0.1167287943576445, 0.08971838503985607, 0.11764879968968978, 0.007020639612302926, 0.15117969006056686, -0.02232561356070556, 0.031206097232611363, -0.5105221856805237, -0.07165923071940816, -0.11540330434037971, -0.42927138008790333, -0.27895384937501455, -0.037080125387024876, 0.18216893596526093, 0.007156941686789153, 0.10994976426506686, 0.07974105089327194, 0.4200182323168536, 0.1010334704249658, -0.09649045230443898, -0.1216745800726689, -0.006433840097155308, -0.7130038986383866, -0.2511433073260203, 0.1889830884515823, -0.31287967914464854, 0.0445363927008785, 0.1756514021724601,}, {-0.10179666879930993, -0.29612278015283316, -0.20679149839900535, -0.04652049895033139, 0.2114764900044822, 0.05270535291540579, 0.24088031182106406, 0.1569026884871254, 0.35155787400042504, 0.2975379607011201, 0.2695677561688946, 0.005474844161084208, -0.0016808128252967514, -0.09079671583076576, -0.2422152698154929, -0.223825025843023, -0.21933531144201723, 0.021959471219431648, -0.15284621880390237, 0.4643935600935616, 0.29674395426048505, 0.04569040868233165, 0.4429941970877582, 0.05408936338863368, -0.10066679644069354, -0.1510158889671891, -0.07896171175964646, -0.083143158360523, -0.3459932514783437, 0.023372145848175152, -0.06282152611686753, -0.034969155303434585,}, {0.02914600251415156, 0.08813679294336718, -0.1633291420600238, -0.05563312410233853, -0.19549889482470806, -0.22567093650359393, -0.08527985288948381, -0.06703929885002757, 0.1181086930233684, -0.08518492314489212, -0.07242469546169339, 0.3267759476643478, 0.213079289321993, 0.0513913033069453, 0.33474459258063716, 0.03987903703866108, -0.0049237744003383654, 0.1296255123461808, 0.09551559120000519, -0.15955342214584212, -0.21455233192635603, -0.11296921090191925, 0.15998152608910088, -0.007742170795083991, 0.16350079117501015, 0.20312985487211374, 0.11035157773867077, 0.19320794392682283, 0.28740807536485713, -0.1176997862377494, -0.14391103336673083, -0.06608554260753957,}, {-0.1435112251620517, -0.14224869782375116, -0.07790873208009905, 0.10380575087738914, 0.06990374230548946, 0.1853219055682296, 0.10740198833512571, 0.061720957033832846, -0.04550527107265667, -0.03230626888878274, -0.10385231962782354, -0.16380176733405505, -0.23964910839152487, -0.10979732128023995, 0.01089534738385467, -0.12785365263371862, -0.037617714770318195, 0.11226026436778103, 0.1809248715602898, 0.039379488070130586, 0.0555636037719863, 0.0012630299972902215, 0.03260919830032116, -0.11347116125800111, -0.001085856446314648, 0.11876894453846187, 0.048107389918566223, 0.007897551492719312, -0.0669256674040829, 0.08250747150556714, 0.07472575105888091, -0.0548091722913818,}, {-0.16040508449398355, -0.04968641754831771, -0.009608502951759856, -0.015272239130372817, -0.06193574163029414, 0.05853569044803264, 0.14392830516394062, 0.0015821550392118855, -0.07222938237513188, -0.014025625397541042, 0.0823109698727188, 0.07471649620217147, -0.06884381055869704, -0.015345969500232925, 0.006001428534840647, 0.023472447973332478, -0.022867331153141712, -0.08424496291546557, 0.017850115871909766, -0.0464172782801886, 0.0033509661973977756, 0.0037005141900653404, -0.05904729344553594, -0.013541064337642794, -0.004673602767547691, -0.004536287480937096, -0.013757043571983818, -0.07459781701263757, -0.07759169448316339, 0.0962144856944459, 0.07024043172138983, -0.08219792896581037,}, {0.003182212723605221, 0.00890262921150542, 0.03727382404944091, -0.061575459306055125, -0.046413949792919906, -0.036667235389542085, 0.04241432495341482, 0.012035736517952422, -0.0671287894536019, -0.020607992508318795, 0.05698541806581903, -0.030028523779256577, -0.10091862378607852, -0.09225126944251497, -0.027000101661705356, -0.06476548784973939, -0.06554021283166889, 0.01701274715327572, 0.11360809629379204, 0.09748864435306759, 0.0667231453217757, 0.042721527611074894, 0.02936426535386416, -0.009712498513272633, 0.023573881038020522, -0.0012721991634052632, -0.0035625458381798036, -0.021162313691654833, -0.042874937762724774, 0.0036606363267049635, 0.016242430806003183, -0.05886922324574706,}, {-0.0728291687916851, -0.05985153291996548, -0.01582015360408029, -0.029354435769979315, 0.031049111364842785, 0.040243539133384854, 0.01976903402997593, 0.02852389895777474, 0.030334642375136056, 0.036182228395824634, 0.04797264889187974, 0.033826462551983905, 0.07237616139598702, 0.05393073259104497, 0.01867845728007511, 0.022902947315326272, -0.0028982285119710793, -0.05692921273494267, -0.025156332461671538, -0.05331867883146435, -0.05752773359785879, -0.06781488290840915, -0.05109040966776757, -0.053731463461197754, -0.07769661925359658, -0.05434520779730018, -0.02537991890268776, -0.010937328333370244, 0.012071606120734324, 0.011825654137207042, 0.05351343851745638, 0.061141688278397055,}, {-0.05123964102907408, -0.02053814919522501, 0.016515070388495368, 0.010018772193820857, -0.02843169268225354, -0.03890027663727258, -0.016359173199996274, 0.012938908322953746, 0.021220404680716953, 0.005953894455308806, 0.007185071762363715, 0.010035933577224154, 0.02377644932626244, 0.005744858700911237, 0.012966098462810494, 0.012399710824955223, 0.002931551261880827, 0.015507873735835287, 0.005322771403265092, -0.0027934732329938927, 0.006489974265967091, 0.010959726815515713, -0.004549283310313124, 0.01535238648510906, 0.017076256309332294, -0.0205841041288517, -0.04014568876502422, -0.012996465777976363, 0.020658707029301393, 0.023047939784759608, -0.002700662097465556, 0.006116925961299646,}, {-0.0020941324584390353, 0.00021380356923006438, 0.011045432807262001, 0.018526424769247374, 0.017093712319933507, 0.003836614724276377, 0.0053406055274061726, 0.00975594077091091, 0.01844370260707484, -0.01687668293137634, -0.016497521729881948, 0.0016218575577531258, 0.0194142257950764, -0.021907699765719824, -0.016281357058667734, -0.007986669966269233, 0.014258955983451282, -0.0231063355767388, 0.007980146302785124, 0.014915886366546172, 0.017214197706041268, -0.027724715890324436, 0.008642355212911346, 0.0007260869858227714, 0.015276539423475288, -0.0032928567726291935, 0.008479931702406351, -0.002262332467931172, -0.0013073437063749749, -0.00595097713723769, -0.0059769811847107945, -0.020526966322355733,}, {0.006749903859457063, 0.0022706785408748154, 0.0005577545279661367, 0.0034864789561880327, 0.0073921430483161465, 0.0009670192071293826, -0.007132035961564753, -0.0021553792721993315, 0.00881608177900306, -0.010893479476470957, -0.0015992886887448154, 0.004050323912979215, 0.00012749012958301886, -0.008139954635783875, 0.004605126023064621, -0.004728132308229549, -0.006825660559454327, -0.014311085944261226, -0.003339015993285316, -0.004773775861649288, -0.008532149258390076, -0.007049721196362269, -0.007631703495187603, -0.0025932531678137527, -0.019384132684820643, -0.0006257122279897942, 0.007218684395033592, -0.007262016993023757, -0.005455287884767471, 0.006478315159049952, -0.013769587276001083, -0.0024786374080052465,}, {-0.0008614355901526705, -0.0019186311888628182, -0.006880853428736122, 0.003542797482922827, -0.0019747984682110253, -0.001253780988267117, 0.0037610207878523427, -0.0017356259744142344, 0.006294807913975742, 0.007635617427419028, 0.0007844630681459741, 0.004424558938086631, 0.0013308429279308975, -0.0030207145125781265, 0.0025150719780276387, 0.0020805044495281755, -0.006381500546362107, 0.003119955031252175, -0.0017384498659828163, -8.575121822063103e-06, 0.0017505603461108188, -0.0071307437841543475, 0.0012576311739865842, -0.0003656095394153902, -0.010223526789857607, 0.0018128349699882973, 0.003820959244609401, -0.006585540855339911, -0.0018191064166179771, 0.0015091862698304315, -0.006365297017999705, -0.0020926254454349813,}, {0.0004222818053086774, -0.0007646868365589476, -0.0034297900522910485, 0.00277177475150181, -0.0027594581948107155, -0.0010291604686319955, 0.006580043676121472, 0.0037149107778613466, -0.0010724067771212265, 0.0040347574157233446, -0.0017873613355283702, 0.003467579746744054, 0.006868106115078265, -0.002380321364977789, 0.005090264219590024, 0.00813001267370883, 0.0007232766903897403, 0.004897659487399786, -0.0032854836649415242, -0.0036942058462539062, 0.0007355268370052848, -0.0068941311230067415, -0.0035241706060236133, 0.0012870879857369427, -0.0012833183114732138, 0.002269486724310543, -0.004436254981464938, -0.00569583183545741, 0.0006025223716119577, -0.004201200107928571, 0.004037115252533807, 0.008550340075819673,},},
-			want2:   [][]float64{{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,}, {0.7042949722286378, 0.07890544358773452, 0.00015042351645201826, -0.007877521023294958, 0.19354098403481937, -0.11385455574626965, -0.01237429873306535, 0.1663422420492151, 0.12909709165340882, -0.07391926287944772, -0.04310131887011262, 0.13547706592174247, -0.022879556820117337, -0.06394423933765864, -0.0667673346061588, 0.03370609975512316, -0.20120806529286314, -0.04539951004522119, 0.03560257778205872, 0.07501870893959572, -0.12595488327349524, -0.039556135382465656, -0.07730878100393117, -0.06358731322233815, -0.10447788912572192, 0.04011454369329688, 0.053219210818133876, 0.008912595635269508, 0.0005231539935652035, -0.007925967122213192, 0.021086735974641467, 0.002042981008599504,}, {0.892384790746479, 0.012373620818677523, -0.011470476048415782, -0.07312896837261335, 0.0926731173915071, 0.05165173679453181, -0.02334826204315842, 0.009655262500579753, 0.02039941740215818, 0.04177923039494345, -0.003783181605515664, -0.11308784458834327, 0.1541176725684209, 0.008705019200459031, -0.11123414905020237, -0.034209816379479646, -0.057080759994478245, 0.03431839006120526, -0.04244979021229303, -0.015531696411407465, 0.02220312808498965, 0.07065838339257327, -0.06460328712825529, -0.12676286264593964, 0.1130846736123999, 0.12211003836209658, -0.07615558956602791, 0.011020355379395972, 0.12054295405685825, -0.026161777897873495, -0.04288428456095813, 0.07951877174007418,}, {1.0387026160758546, -0.053808423761913, 0.0013626993192067715, -0.006413366991445299, 0.04376856356485069, 0.03798123274189914, -0.0033588953697255767, -0.00856242816268768, -0.028870310106928698, 0.011507883614301571, 0.01986537805718956, -0.0344020628206266, 0.054298734818521655, 0.0106865293923878, -0.002280263428789369, -0.0693242045199328, -0.0330026115766725, 0.09812355727154681, -0.04464364240104711, -0.1260723287806606, 0.10308237257796439, 0.02870894311774143, -0.13087316452429504, 0.020242085389622785, 0.028464607293187442, 0.05521155490542, 0.002621968964203771, 0.030027862363685005, 0.04160178079630213, 0.013427722075858895, 0.024982299902937752, -0.03086549144475149,}, {0.9873640540595163, -0.06182649870903511, -0.016339200497713542, -0.004556050781336997, -0.06589440586852494, 0.040255871460258746, 0.08200872635942336, -0.0077599150883276916, -0.02448513318344384, 0.04753228450854069, 0.0190099020251979, -0.010084074688490494, -0.012028723184121837, -0.0517403885667202, -0.027943257617157498, 0.028751456679228, -0.07448348400509042, -0.036157808103421896, 0.013683619816547667, 0.03667425317639582, -0.023793320607107718, -0.02718773789691383, 0.004376364951581452, 0.0435424016654073, -0.05349594479804775, 0.031004133581013282, 0.03523633714212243, -0.01677338043651609, -0.027282086727120278, 0.030147833010423883, 0.0016585799414572727, -0.03506955712298371,}, {1.003453416165895, 0.023437505704121748, 0.03864435807844013, 0.017098688687596668, -0.013216897001950365, 0.016223589335925326, 0.041701368218548265, -0.043182065129763685, -0.06844868777747518, 0.04015184703223279, -0.02251610562658387, 0.01219240498036777, -0.012968048533428967, 0.013205779525656006, 0.027990071199846648, 0.03221837752130195, -0.010789077153681183, -0.0036880008095290834, 0.0015034648718915988, 0.03472772042408144, 0.01019703052561589, -0.07895947734445685, -0.0029645322219777124, -0.00019742536656473423, -0.02469477995267505, -0.029562144520250265, 0.0407551422697307, 0.013428887094172708, 0.011560059878926072, 0.05534180694728458, 0.02175050142088604, 0.02650112608074049,}, {0.9935104404904767, -0.013524224176066066, -0.023198005325695763, -0.020539987526467577, -0.052195859949748694, 0.008164356611673, 0.014925764237798449, -0.016948897672760543, -0.0199640197653939, 0.05223643521522833, 0.04329557894219259, 0.02656497317837766, -0.009898466235380605, -0.02203923946433376, -0.02870913399035538, -0.018109253314052866, -0.06998364705965754, -0.055674840012736565, -0.03481028555178532, 0.012154266939455129, 0.02432813867184337, -0.018767201374657913, 0.05530886159180846, 0.05765994001863066, 0.0033420535487892985, -0.007261638758270593, 0.006058108420270508, -0.022624349999437035, -0.029056208668290937, -0.037645294724127476, 0.00043403481901379464, 0.0006540163020159693,}, {0.982425643266132, 0.004731917777102501, 0.008065380795336117, -0.008373750187571843, -0.016952898501506684, 0.022936334778318947, 0.0018994895627553702, 0.012419910460131899, -0.0023210844501224946, 0.011469530365680176, 0.04277244500368654, 0.03417734328673935, 0.001078894436020711, 0.005760601258006417, -0.007172698038538911, -0.0165320518807546, -0.05178594623925503, -0.04393949780995824, -0.0008714768477796125, -0.0013028924922732278, -0.01855699205018278, -0.0105578746460253, 0.03692351842254673, 0.04687606139822508, 0.018253722795969672, -0.03025016981882677, -0.008719695901940605, -0.0015240440750781217, 0.002802877743652163, 0.004704663512478257, 0.015449828476246643, 0.03246477704158278,}, {0.9974239840058052, 0.004636660998944017, 0.00812535056932432, -0.0003861811197809175, 0.003578812419744365, 0.031567799485144925, 0.006851351615236192, 0.01116491712113557, 0.001919373122755351, 0.000941456064190241, 0.02446748942693959, 0.02453254097791794, -0.0023547703276007243, 0.0044129024245847075, 0.0070517602960741945, -0.0012128634997065585, -0.03658360313224022, -0.02686551802087426, -0.0039222073383385105, -0.0006977412654847416, -0.021594225183744008, -0.013056156502078729, 0.012797196824608885, 0.024551575765636643, 0.003126233684205111, -0.018563012624837463, -0.0035137418913383897, 0.007703400038794777, 0.008211880217652151, 0.004951764577635248, 0.003951287867607087, 0.017828788275153388,}, {0.9985280319158562, 0.003510842512907346, 0.001802018107304943, 0.0035821580675559324, 0.007547800026648618, 0.013615511382114334, 0.011388381010393241, 0.012835236603516062, 0.0031290539971249487, 0.0032028404649014465, 0.011395824376417207, 0.017729092780943416, 0.0018003150529190765, 0.005773420916643705, -0.00014149693084727265, -0.005392137582453923, -0.02737276149346637, -0.019361287884769745, -0.0059239944588516695, -0.00958181048661531, -0.01434217596045044, -0.003137908588502482, 0.005092841445807794, 0.018772580487084484, -0.000589111670234089, -0.008012781535275538, -0.0018472109116024924, 0.00361463117350639, 0.007250333208611907, 0.0009997934939699601, 0.001867513511711068, 0.012179055526033749,}, {0.9945044057059123, 0.004560743947629873, 0.0013677196586589857, -0.005053282595002878, 0.003299121156406659, 0.014966092696927457, 0.0073675234062027094, 0.011283130463703822, 0.006696724153439176, 0.0024695466353354123, 0.003241324817404017, 0.0032470718485825036, -4.739586643171575e-06, 0.005329736383382041, -0.005222155525573476, -0.0034480286910595014, -0.010522234060882306, -0.007259404126901566, -0.006278457812558544, -0.009672101802717895, -0.007252269861379147, -0.0011569848259358716, -0.004957580043880135, 0.00260534630597979, -0.0008573424020232314, -0.006574997450564715, -0.004957194844255527, 0.0057432194004339255, 0.010357567336172276, 0.003900457437281329, 0.0016174773889693103, 0.004073593284694409,}, {1.00066621193294, 0.005809764307654848, -0.0022248117999908125, -0.007145285710381088, -0.005135797545477925, 0.0015326432366799558, -0.002826205217424345, -0.0016931250880854506, -0.0021329353105317647, -0.0026799354360490463, -0.005554079728523135, -0.0005810235635596449, -0.0009431754881455179, 0.003965304568812587, -0.00014775515584748478, 0.0002666006653209643, -0.0007259682568288528, 0.004577395815252024, 0.0044144414975536, 0.0010246136833645145, -0.0006516693825382959, 0.004656090170149587, -0.0006455905390145899, 0.0014954410085455012, -0.0024629234250043205, -0.00413197866279228, -0.0026467103219450713, 0.0012011417382440399, 0.0016387082155752577, -0.004322737862661108, -0.009031776531119909, -0.006363853523831972,}, {1.0008554900879651, 0.0017809266593761558, -0.003881119427843193, -0.0016167652718108605, -0.00077811669188051, -0.0003990997917993201, -0.004472957495324818, -0.0018405598748945681, -0.001101469454655819, -0.0019648579429150663, -0.00475207591385142, 0.00028710586765633356, -0.0010772377126753803, 0.0012762843972383125, -0.0017931432319489681, 0.0010744403266111, -5.059968519481204e-05, 0.003495292140215382, 0.0019533812098758625, 0.00028553458691071, -0.0002517641330007502, 0.003950301109243824, -0.002104219953868898, -0.0020142340477964727, -0.00048172495753763116, 0.0008359898613194245, -0.002299384689940628, -0.001193537400408339, -3.1291485415194112e-06, 0.0014035583435718637, -0.004119525538727182, -0.004142554981567402,}, {0.9992350106954548, -0.00025816328340932585, -0.0016898527548068697, -0.0013428108540988171, -0.0022956513294683737, -0.0031222038919045086, -0.0017700184939604408, -0.0008766494224060102, 3.778906044554286e-05, -0.0008281640783747036, 4.747617213151113e-05, -0.00027851764884769795, -0.0006888909909997588, -0.0012271021740084096, 0.0011121301670014706, 0.0005241015756600857, 7.276453990400232e-05, 0.00033176541398080843, 0.0026461725857558466, -0.0009746598464587637, -0.0003088826289906029, 0.0009137788067280048, 0.00014450586369860484, 0.0004743020992531183, 0.0001952347355927507, -0.00013438670876584756, 5.106191674828974e-05, 0.00016745028682958372, -0.0014133640795168144, -0.00012472471142103503, -0.002491111635148552, -0.001957559473364617,}, {1.0000584244808475, 0.00019584116743425704, -0.00024992516069208173, -0.0009887888507017415, -0.0013469052957391736, -0.0009414542057494759, -0.0016231469913125423, -7.779570252614801e-05, 8.788433229823251e-05, -0.00038422952503711186, 0.00038880902673962657, -0.0004316675889138123, -0.0008771238529722026, 0.0013702262402920614, -3.5938473242968664e-05, 0.00010071149395978146, 0.001510623127691033, 0.0005262625659231254, 0.0004041850138460487, 0.001046834619498854, 0.0006800156978408491, -0.0007684809158340319, 0.00041605572659029544, 0.0004955044910190476, -0.00021177890660305817, -0.00021227327376710772, 0.000134664774120679, 0.0009879618018264476, -0.0005531166475792829, -0.00203172044217717, -0.0008502209436755143, -0.0012278805593464275,}, {1.0005170365164937, 0.0007564189404971728, -0.000760133950851314, -0.00033781154209225285, -0.0007266113576782204, -0.0014916475786442291, -0.0009694682913348563, 0.00033836248086279157, -0.0006424557651860032, 0.0001112132533541482, -9.622198326872039e-05, -0.0009684273915274007, -0.0002666613835952927, 0.000176145132379985, 0.00020864444183412798, 0.0007291942023809734, 0.0001157114266169091, 0.0011150714611746423, 0.0004395160437069164, 0.0001542291829922237, 0.0009205652302462606, -0.0007001745341772827, 9.318570500580667e-06, 0.0007254460218602023, -0.000931356219355257, -0.00017472648493032443, 0.00035758439069910304, -0.0005091157906866017, 0.00025025598532597083, -0.001225373937207543, -0.0014585676734938336, -9.663586657718493e-05,},},
+			want:    [][]float64{{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,}, {0.23003869082686101, -0.6901593212914543, -0.8440699784810924, -0.4857954917260755, 0.3644853017621912, -0.22481006401828385, -0.7668909314995308, -0.1422921669957143, 0.282055079891256, 0.4917920849513089, -0.5016290327596836, 0.5633983877355005, 0.3744250287126774, -0.6238856651769877, -0.11237272782464405, 0.4927372011155191, 0.5646477750031937, -0.13465236941900058, 0.23787942442779555, 0.3359435892429725, 0.09137768008194255, -0.1496986753933931, -0.3456856652164134, 0.5608389393574055, 0.3416658112375058, -0.12850013436736868, -0.35103156909261957, -0.08076539657311943, -0.4075353204616491, 0.04081843404606034, 0.4959020874852492, 0.02683606500313465,}, {-0.27499507652163024, 0.37241711636910807, -0.46935920436449824, -0.03801556431375286, 1.1595203743390015, -0.11561580139671535, -0.32247812636087636, 0.5067858245098571, -0.2964753204537083, -0.28725446898049056, -0.1504691398724548, -0.7687164924974492, -0.9336010714246912, -0.17353208072235987, 0.7370353491876168, -0.1699436103724422, 0.15937633437161491, -0.1396434823090453, 0.37346334131118575, -0.24277422851618144, -0.6183970415112086, -0.24014158268126973, -0.33240423770987154, -0.9827378696530507, 0.2553146844635943, 0.20092524251706154, -0.5843425805852636, 0.061322802497484064, 0.2507305352103438, -0.33226421149107066, -0.012933890241475177, 0.33744505389498003,}, {1.2255322818386682, -0.023032707064123442, -0.4309041356302845, -1.086124110803688, -0.39624719140627684, -0.4265412505768427, 0.11296231465856194, -0.7633529899580153, 0.9131348659291167, 0.9771299568939031, 0.27693675818077207, -0.31892042877364224, 0.2713491821096379, 0.33332826298720125, -0.5300553173701592, -1.3533814045717891, -0.11329289522262354, -0.3321429591227747, 1.4064296570211188, -1.0923507482821178, 0.10005516671954467, -0.17988077619965281, 0.43555266393774866, -0.5768906800630044, 0.32586590903441975, 0.4285880733462112, -0.5234005278132918, -0.5342871154323856, -0.4925580005138148, 0.23117296587437697, -1.1215338050822383, -0.17193717723876045,}, {-0.7048422601729258, 0.7231855889302815, -0.5920170591846576, 0.3430031364729068, 0.4919039705662117, -0.22333282483287487, 0.2466563670827674, -0.6099953247742388, 0.8638670098538297, 0.018023647720572356, -0.7896024172121514, 0.15871696350765002, -0.756907166007499, -1.0783087682566526, 0.5518091793086588, -0.20798197238421978, -0.4037642992260594, 0.024581160130207128, -0.4511300849232045, 0.9399539782820677, 0.16721061031372894, 0.09173952656215531, -0.6586935365751284, -1.0668086936429826, 0.1489808347272998, -1.2102256321012816, 0.4593063048810009, 0.24349080559831557, 0.13245611111855787, 0.5054141537233992, 0.4923002817905044, -0.05029877966491306,}, {-0.42423760311701797, -0.18520835676138384, -0.7613359441407747, -0.7332775020967428, -0.4165848439326496, -0.7683124293663117, -0.807737169303842, -0.8190056228455915, -1.3469054940899627, -0.13939437064738205, -0.07938972586091553, 0.117032806200373, 0.7413278800163645, -0.30924639246550656, 0.49561318990329867, 0.898422390238699, 0.4430436906347031, 0.2713293522311611, -0.5399065145923587, 0.6761052499576885, -0.7163804548661665, 0.22209261782370754, 0.4494066542892172, -0.6659624812878027, -0.6054908178606054, 0.19066300943937597, 0.038712658960279124, 0.38683429012103177, 0.587654169783219, -0.5069756779901375, 0.18282211961141642, 0.1238297308810894,}, {-0.2649067266659029, 0.44555642168493215, -0.18698692738223738, 0.006971892879872543, 0.6355307199055148, 0.1637816309111058, -0.02355339290537173, 0.22350617413389473, -0.25692446256337087, 0.37378558447753063, 1.7013991560140562, 0.3464937114091367, 0.0502950165797022, -0.22028882340557282, 0.6354589552515295, -0.11955110731717522, -0.06963940374127087, 0.31186637182477944, -0.010742102725759761, -0.652430828984975, 1.000001109316756, 0.31261889351770783, -0.32885351227142756, 0.21986617861540558, -0.2741743387796495, -0.014809111418643425, -0.5029500069608113, -0.6959083409637863, -1.2318007538113482, 0.16502484252692823, -0.4478636325335281, -0.0419771316323759,}, {0.05448708547171678, -0.32065260525494677, -0.09845100923602824, 0.19160456464090694, -0.20868493789373643, 0.1245225526607762, -0.3700143408515635, -0.5641281010541903, -0.18552516281106496, -0.3802345815110488, 0.193985767880948, 0.12481639291114294, -0.23743536251952024, 0.09982936424450398, -0.008758948750362927, -0.5226514453324823, -0.010349136769533293, -0.18441733078500003, 0.328996763969192, -0.5388037448240094, -0.060952394603383475, 0.856739968235942, 0.6683539285565557, 0.01699239569573488, -0.37617376074298525, 0.37764895369965135, -0.295528807486563, 0.699828204233617, -0.17883013636831685, 0.5603026221619396, -0.27020989370406784, -0.04433912728334864,}, {0.10889155391867758, -0.35451172766259603, 0.6541484453447557, 0.02037248611200898, 0.8325692496519617, -0.2151966001686158, -0.3153037717218455, 0.05164887961790443, -0.30446751418205453, 0.6037256052211672, -0.008218543958131763, 0.09278455266204566, 0.45638458040747437, -0.19627404087471506, 0.24824249606731624, 0.0002493555546377868, -0.12606827660405442, 0.918127866741687, 0.10200807388110461, -0.7127837755090487, -0.18532517497352233, -0.2392341970179716, 0.6502855048531835, 0.5291367995941676, 0.3285051725011371, -0.17830177340033063, 1.0895059160884002, -0.8221369047132334, -1.0621172848387785, 0.2988674546950538, 0.2075347570633752, -0.03305291453359666,}, {0.08911272560117271, 0.3849001084829962, 0.2998642631540156, 0.642818896266023, 0.5778345142781012, 0.7869559671436019, -0.04863013995101072, -0.9844405326856904, 0.37243090613878527, -0.048736304267808006, 0.12885078556071639, -0.09886955579718042, 0.3949260337229919, -0.0025417255910122755, -0.2107290846412754, 0.0062687764865448845, -0.30756395193271047, 0.34605806465424505, -0.10125775593587477, -0.4106091379234219, -0.3536038182176615, 0.2473480505297865, 0.5936942113084275, -0.05889556468355056, -0.10396658139292526, 0.9487830250581364, -0.006965650107321197, 0.22666210434870415, 0.3096673533406112, -0.040213639838701826, -0.32195118313974225, -0.8015212857226508,}, {-0.5448118644487736, -0.20532311432173356, 0.4662733450757556, -1.0680396840513544, 0.8613186231693113, -0.40589874707350415, -0.1690299963521014, -0.833796731490614, -0.9712323073065994, 0.6286246033031084, 0.03405674330493891, 0.16853106934586412, -0.10941588470841085, 0.5995085478323761, 0.557713615686432, 0.3970251656359731, 0.19480124742176225, -0.18679190239225912, 0.014700214880542073, 0.4281028754710854, 0.31416051609524354, -0.2119511972515847, 0.41266261542905625, 0.009907695234091976, -0.00924025281946407, 0.07372477548347062, -0.6146744737729817, 0.3531714272704819, -0.23378318923766422, -0.5474773026900439, 0.3640726214360809, 0.612635330426343,}, {0.3132838750118886, -0.45863414790714485, -0.0022278346230788226, -0.20804679960555755, 0.3136123775465311, 0.0687805204279115, -0.033820537969791675, -0.18814590496292716, -0.054698222466758484, 0.08098322713247341, -0.2610225064374604, 0.06848933790598899, 0.46883149661236956, 0.45658228728363304, 0.422866076484255, 0.05019795344481781, -0.6859399266465247, 0.47414378707455673, -0.15542532151303223, -0.039941473831791, -0.09175514174332289, 0.28420157246926714, -0.754589875322447, -0.47480576628343224, 0.496479186509475, 0.6781125112043578, 0.693492730328867, -0.02036818916955016, 0.9063728147622176, 0.7110635249499545, 0.05144316328649157, -0.3637747651743719,}, {-0.18827619884829092, -0.4514574596288691, 0.6046925580879204, -0.4974041014403061, 0.41244931139631685, -0.14161239401174844, 0.17811859523583715, -0.9330485360853626, -0.2988722570084986, 0.378841093782584, 0.8593192182350412, -0.2907711054198612, 0.19468001927536108, -0.3455323303643708, -0.6025641114164113, 0.14918325122686799, 0.3662956161163167, 0.1626218451949521, -0.08002610883593068, -1.0740915266038509, 0.33511621032163275, -0.09087099407492927, -0.765374163304337, 0.5554964757000656, -0.31351801051045775, -0.21909963471797522, -0.28275312890094084, 0.7047241137685979, -0.1609957364836247, -0.15681497195299865, 0.45766109323941595, 0.49967058998491143,}, {-0.25938980663136774, 0.7508658969555677, -0.3602480331713259, -0.36084154447856653, -0.6777553438208536, -0.7497048192519093, 0.5713973506947196, 0.8224485880191579, -0.7136402302589016, 0.15450454109583042, 0.3939497572236752, -0.9151666628588441, 0.3152275330980327, -0.006276399152299217, -0.3659358075410625, -0.5207082806975863, 0.629930085029095, -1.1720277240862915, 0.09755777966903971, -0.6757836608239283, -0.5292194466377003, 0.7455371342235421, -0.34211382584731515, 0.0853661793836179, -0.3465839808686125, -0.5053270165662055, 0.13934939279449826, -0.9665209534789663, -0.03798942660004571, 0.6904567780888355, -0.4341764982447427, -0.6114362112233765,}, {-0.19295621284170403, -0.5121735107295611, 0.15078409682443994, 0.1154434296483721, 0.6119499519353511, 1.303952147999127, -0.39459624857544545, -0.48976357364030915, 0.5829705300566095, -0.4112065546201549, 0.06679665376815985, 0.17091942702006346, -0.22902555225162732, 0.15399523705628498, 0.1719258826990069, 0.07453378680841097, -0.5656181194240854, 0.4655383755938329, 0.3968013064841871, 0.336156394763224, -0.11705951729731681, -0.5427915031040607, 0.4921850175171184, -0.05673187238533081, -0.4552494239239123, 0.3048078327898317, 0.0022271232288322562, 0.33927838300330204, -0.5494812802019124, -0.6105960468064175, -0.30396027910760626, 0.3240453300503039,}, {-0.4821765640616651, 1.2770107216073598, 0.4141782162262173, 0.41175542140954147, 0.36648802349415965, 0.017521796127025757, 0.15731783803644198, 0.011136127798355433, -0.15521482839116846, 0.6694373527546347, 0.4321258554286682, -0.42051862544262897, 0.4729521367404209, 0.5870477696594698, 0.12735975624750853, -0.32640581911893074, -0.6879298485536988, 0.1459370331891998, -0.18371414306360784, 0.41641055496060886, -0.4344699515114486, 0.2659518714278518, 0.534647170504544, -0.0029746191051094814, -0.09442832630737213, 0.07436018534556435, 0.5073460021861826, 0.4469994084571842, 1.0229456472792973, 0.24165156336699567, 0.7057458827797777, -0.29512211090159823,},},
+			want1:   [][]float64{{-0.6168790887989735, -0.06317375535118647, -0.26049728557657514, 1.14285955884979, 0.16140262630578994, 0.29503364379984687, 0.07940387008821781, 0.4946010421477909, -0.3656415080887395, 0.34319039251798633, 0.7927019811403115, 0.4191029522104053, 0.6494204237587171, 0.2636791965299308, 0.3662209629022566, -0.5365899105443762, 0.3500604512199924, 0.2157653593480266, 0.49981306050563123, -0.7619838362639466, -0.15826862144704412, 0.9447321031317408, 0.5503645968750104, -0.49637159537571834, 0.4948552101042658, -0.30761174263888247, -0.7175234610661141, -1.0757183413713223, 0.06867518678667539, 0.2214113135132833, -0.42304718672779856, -0.041397517068074596,}, {-0.1519754431854537, -0.035152304793781286, 0.9839418378126782, -0.3836577071781707, -0.013110170981100566, 0.3978795120957317, 0.2321182303230206, -0.2743337875991558, -0.11691038708492513, 0.3810787809376648, -0.058728013418632985, -0.1849989668121524, 0.0892242066693642, -0.10386724049917995, 0.5988899187462366, -0.6403440280282402, -0.3095897197601857, -0.10141603482893338, -0.11210940239822095, -0.3769123250097571, -0.00803232062279377, 0.33172123465867087, -0.4736348737118038, -0.1430047608444487, 0.23544959561751283, 0.09098196055997207, -0.03424890483227733, -0.4808811585564583, 0.01942871552476555, 0.2951534507922636, 0.21423766090919083, -0.21122529537681592,}, {0.10976669528414545, -0.4044341619821592, 0.3458418459493802, 0.12264104022929628, -0.29170374601172067, -0.01848501841074071, 0.2195611627324464, 0.0951933707066529, -0.22935732834826372, 0.03908077042418634, -0.18829263325911483, -0.17980483725103436, -0.047217884117763065, -0.19419015921242694, 0.2559242918029493, 0.2173531690499262, -0.17994538840611116, 0.050657343476808364, 0.22840246593202285, -0.2606702872142933, 0.1991006602515335, 0.2908520726060196, -0.36914212647696104, -0.18047705794771685, 0.2318268974807165, -0.07762215098241836, -0.41213860441047767, 0.24909788359674911, -0.060113665060368526, -0.15778813449892726, 0.21771948752120002, -0.05190396542171205,}, {0.03958693983753503, 0.2085437417232825, -0.20421722361746208, -0.0032814584888449794, 0.1859332850956151, -0.0022095862537331867, -0.11188198035724312, 0.16711621256785325, 0.27829530678685366, -0.23759822737538827, -0.18130369928046824, 0.32031200783585195, -0.2663460831172184, -0.36923451074639907, 0.30089889326703956, 0.26991940414575377, -0.12493589426047097, 0.05348837826761477, -0.040868721508895645, 0.3642710849381451, -0.07381984739590988, -0.12939927940344192, -0.03561446286096587, 0.12438617479632674, 0.03680283493743591, -0.2468622888192718, -0.04628741012773019, 0.22394353301512748, -0.015003839804274444, -0.15943371643935678, 0.09181290688694155, 0.1464815792403868,}, {0.05409153750706863, -0.06617368780356248, 0.000731097389339519, -0.025590187952765464, 0.05836439717882225, 0.04485919251992804, 0.05882439984484489, 0.003510319806151463, 0.07558984503028343, -0.01116280678035278, 0.015603048616305681, -0.2552610928402618, -0.03582961535970408, -0.057701652170189854, -0.21463569004395167, -0.13947692468750728, -0.018540062693512438, 0.09108446798263047, 0.0035784708433945767, 0.05497488213253343, 0.03987052544663597, 0.2100091161584268, 0.0505167352124829, -0.04824522615221949, -0.06083729003633445, -0.003216920048577654, -0.3565019493191933, -0.12557165366301015, 0.09449154422579115, -0.15643983957232427, 0.02226819635043925, 0.08782570108623006,}, {-0.050898334399654965, -0.14806139007641658, -0.10339574919950267, -0.023260249475165695, 0.1057382450022411, 0.026352676457702895, 0.12044015591053203, 0.0784513442435627, 0.17577893700021252, 0.14876898035056005, 0.1347838780844473, 0.002737422080542104, -0.0008404064126483757, -0.04539835791538288, -0.12110763490774645, -0.1119125129215115, -0.10966765572100862, 0.010979735609715824, -0.07642310940195118, 0.2321967800467808, 0.14837197713024253, 0.022845204341165826, 0.2214970985438791, 0.02704468169431684, -0.05033339822034677, -0.07550794448359455, -0.03948085587982323, -0.0415715791802615, -0.17299662573917185, 0.011686072924087576, -0.031410763058433766, -0.017484577651717292,}, {0.01457300125707578, 0.04406839647168359, -0.0816645710300119, -0.027816562051169266, -0.09774944741235403, -0.11283546825179697, -0.042639926444741905, -0.03351964942501379, 0.0590543465116842, -0.04259246157244606, -0.036212347730846695, 0.1633879738321739, 0.1065396446609965, 0.02569565165347265, 0.16737229629031858, 0.01993951851933054, -0.0024618872001691827, 0.0648127561730904, 0.047757795600002595, -0.07977671107292106, -0.10727616596317802, -0.056484605450959624, 0.07999076304455044, -0.0038710853975419957, 0.08175039558750508, 0.10156492743605687, 0.055175788869335385, 0.09660397196341142, 0.14370403768242856, -0.0588498931188747, -0.07195551668336542, -0.033042771303769786,}, {-0.07175561258102585, -0.07112434891187558, -0.03895436604004952, 0.05190287543869457, 0.03495187115274473, 0.0926609527841148, 0.053700994167562854, 0.030860478516916423, -0.022752635536328336, -0.01615313444439137, -0.05192615981391177, -0.08190088366702752, -0.11982455419576243, -0.05489866064011997, 0.005447673691927335, -0.06392682631685931, -0.018808857385159097, 0.05613013218389051, 0.0904624357801449, 0.019689744035065293, 0.02778180188599315, 0.0006315149986451107, 0.01630459915016058, -0.056735580629000554, -0.000542928223157324, 0.05938447226923094, 0.024053694959283112, 0.003948775746359656, -0.03346283370204145, 0.04125373575278357, 0.037362875529440454, -0.0274045861456909,}, {-0.08020254224699178, -0.024843208774158854, -0.004804251475879928, -0.007636119565186408, -0.03096787081514707, 0.02926784522401632, 0.07196415258197031, 0.0007910775196059427, -0.03611469118756594, -0.007012812698770521, 0.0411554849363594, 0.037358248101085734, -0.03442190527934852, -0.007672984750116463, 0.0030007142674203235, 0.011736223986666239, -0.011433665576570856, -0.042122481457732786, 0.008925057935954883, -0.0232086391400943, 0.0016754830986988878, 0.0018502570950326702, -0.02952364672276797, -0.006770532168821397, -0.0023368013837738455, -0.002268143740468548, -0.006878521785991909, -0.037298908506318784, -0.038795847241581694, 0.04810724284722295, 0.035120215860694914, -0.04109896448290518,}, {0.0015911063618026106, 0.00445131460575271, 0.018636912024720453, -0.030787729653027562, -0.023206974896459953, -0.018333617694771043, 0.02120716247670741, 0.006017868258976211, -0.03356439472680095, -0.010303996254159398, 0.028492709032909513, -0.015014261889628289, -0.05045931189303926, -0.04612563472125748, -0.013500050830852678, -0.03238274392486969, -0.032770106415834444, 0.00850637357663786, 0.05680404814689602, 0.048744322176533794, 0.03336157266088785, 0.021360763805537447, 0.01468213267693208, -0.004856249256636316, 0.011786940519010261, -0.0006360995817026316, -0.0017812729190899018, -0.010581156845827416, -0.021437468881362387, 0.0018303181633524818, 0.008121215403001592, -0.02943461162287353,}, {-0.03641458439584255, -0.02992576645998274, -0.007910076802040145, -0.014677217884989657, 0.015524555682421393, 0.020121769566692427, 0.009884517014987965, 0.01426194947888737, 0.015167321187568028, 0.018091114197912317, 0.02398632444593987, 0.016913231275991952, 0.03618808069799351, 0.026965366295522486, 0.009339228640037556, 0.011451473657663136, -0.0014491142559855397, -0.028464606367471335, -0.012578166230835769, -0.026659339415732175, -0.028763866798929394, -0.03390744145420457, -0.025545204833883783, -0.026865731730598877, -0.03884830962679829, -0.02717260389865009, -0.01268995945134388, -0.005468664166685122, 0.006035803060367162, 0.005912827068603521, 0.02675671925872819, 0.030570844139198528,}, {-0.02561982051453704, -0.010269074597612504, 0.008257535194247684, 0.005009386096910429, -0.01421584634112677, -0.01945013831863629, -0.008179586599998137, 0.006469454161476873, 0.010610202340358477, 0.002976947227654403, 0.0035925358811818575, 0.005017966788612077, 0.01188822466313122, 0.0028724293504556186, 0.006483049231405247, 0.006199855412477612, 0.0014657756309404135, 0.0077539368679176435, 0.002661385701632546, -0.0013967366164969464, 0.0032449871329835456, 0.005479863407757857, -0.002274641655156562, 0.00767619324255453, 0.008538128154666147, -0.01029205206442585, -0.02007284438251211, -0.006498232888988181, 0.010329353514650697, 0.011523969892379804, -0.001350331048732778, 0.003058462980649823,}, {-0.0010470662292195176, 0.00010690178461503219, 0.005522716403631001, 0.009263212384623687, 0.008546856159966754, 0.0019183073621381885, 0.0026703027637030863, 0.004877970385455455, 0.00922185130353742, -0.00843834146568817, -0.008248760864940974, 0.0008109287788765629, 0.0097071128975382, -0.010953849882859912, -0.008140678529333867, -0.0039933349831346165, 0.007129477991725641, -0.0115531677883694, 0.003990073151392562, 0.007457943183273086, 0.008607098853020634, -0.013862357945162218, 0.004321177606455673, 0.0003630434929113857, 0.007638269711737644, -0.0016464283863145968, 0.004239965851203176, -0.001131166233965586, -0.0006536718531874874, -0.002975488568618845, -0.0029884905923553973, -0.010263483161177867,}, {0.0033749519297285313, 0.0011353392704374077, 0.00027887726398306834, 0.0017432394780940164, 0.0036960715241580733, 0.0004835096035646913, -0.0035660179807823766, -0.0010776896360996657, 0.00440804088950153, -0.005446739738235479, -0.0007996443443724077, 0.0020251619564896073, 6.374506479150943e-05, -0.004069977317891937, 0.0023025630115323104, -0.0023640661541147745, -0.0034128302797271637, -0.007155542972130613, -0.001669507996642658, -0.002386887930824644, -0.004266074629195038, -0.0035248605981811343, -0.0038158517475938014, -0.0012966265839068764, -0.009692066342410322, -0.0003128561139948971, 0.003609342197516796, -0.0036310084965118783, -0.0027276439423837354, 0.003239157579524976, -0.0068847936380005414, -0.0012393187040026232,}, {-0.00043071779507633523, -0.0009593155944314091, -0.003440426714368061, 0.0017713987414614135, -0.0009873992341055127, -0.0006268904941335585, 0.0018805103939261714, -0.0008678129872071172, 0.003147403956987871, 0.003817808713709514, 0.00039223153407298705, 0.0022122794690433156, 0.0006654214639654488, -0.0015103572562890633, 0.0012575359890138194, 0.0010402522247640877, -0.0031907502731810533, 0.0015599775156260876, -0.0008692249329914081, -4.2875609110315516e-06, 0.0008752801730554094, -0.0035653718920771738, 0.0006288155869932921, -0.0001828047697076951, -0.005111763394928803, 0.0009064174849941486, 0.0019104796223047005, -0.0032927704276699554, -0.0009095532083089886, 0.0007545931349152157, -0.0031826485089998524, -0.0010463127227174907,}, {0.0002111409026543387, -0.0003823434182794738, -0.0017148950261455242, 0.001385887375750905, -0.0013797290974053578, -0.0005145802343159978, 0.003290021838060736, 0.0018574553889306733, -0.0005362033885606132, 0.0020173787078616723, -0.0008936806677641851, 0.001733789873372027, 0.0034340530575391326, -0.0011901606824888944, 0.002545132109795012, 0.004065006336854415, 0.00036163834519487015, 0.002448829743699893, -0.0016427418324707621, -0.0018471029231269531, 0.0003677634185026424, -0.0034470655615033707, -0.0017620853030118067, 0.0006435439928684714, -0.0006416591557366069, 0.0011347433621552716, -0.002218127490732469, -0.002847915917728705, 0.0003012611858059788, -0.0021006000539642855, 0.0020185576262669036, 0.004275170037909837,},},
+			want2:   [][]float64{{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,}, {0.3521474861143189, 0.03945272179386726, 7.521175822600913e-05, -0.003938760511647479, 0.09677049201740968, -0.05692727787313483, -0.006187149366532675, 0.08317112102460755, 0.06454854582670441, -0.03695963143972386, -0.02155065943505631, 0.06773853296087123, -0.011439778410058669, -0.03197211966882932, -0.0333836673030794, 0.01685304987756158, -0.10060403264643157, -0.022699755022610596, 0.01780128889102936, 0.03750935446979786, -0.06297744163674762, -0.019778067691232828, -0.038654390501965584, -0.03179365661116908, -0.05223894456286096, 0.02005727184664844, 0.026609605409066938, 0.004456297817634754, 0.00026157699678260173, -0.003962983561106596, 0.010543367987320734, 0.001021490504299752,}, {0.4461923953732395, 0.006186810409338762, -0.005735238024207891, -0.03656448418630667, 0.04633655869575355, 0.025825868397265906, -0.01167413102157921, 0.004827631250289876, 0.01019970870107909, 0.020889615197471723, -0.001891590802757832, -0.056543922294171635, 0.07705883628421045, 0.0043525096002295155, -0.05561707452510119, -0.017104908189739823, -0.028540379997239122, 0.01715919503060263, -0.021224895106146516, -0.007765848205703732, 0.011101564042494825, 0.035329191696286635, -0.032301643564127645, -0.06338143132296982, 0.05654233680619995, 0.06105501918104829, -0.03807779478301396, 0.005510177689697986, 0.060271477028429124, -0.013080888948936747, -0.021442142280479064, 0.03975938587003709,}, {0.5193513080379273, -0.0269042118809565, 0.0006813496596033857, -0.0032066834957226497, 0.021884281782425345, 0.01899061637094957, -0.0016794476848627884, -0.00428121408134384, -0.014435155053464349, 0.005753941807150786, 0.00993268902859478, -0.0172010314103133, 0.027149367409260827, 0.0053432646961939, -0.0011401317143946844, -0.0346621022599664, -0.01650130578833625, 0.049061778635773406, -0.022321821200523555, -0.0630361643903303, 0.051541186288982195, 0.014354471558870714, -0.06543658226214752, 0.010121042694811393, 0.014232303646593721, 0.02760577745271, 0.0013109844821018856, 0.015013931181842502, 0.020800890398151067, 0.0067138610379294476, 0.012491149951468876, -0.015432745722375745,}, {0.49368202702975816, -0.030913249354517554, -0.008169600248856771, -0.0022780253906684983, -0.03294720293426247, 0.020127935730129373, 0.04100436317971168, -0.0038799575441638458, -0.01224256659172192, 0.023766142254270345, 0.00950495101259895, -0.005042037344245247, -0.006014361592060919, -0.0258701942833601, -0.013971628808578749, 0.014375728339614, -0.03724174200254521, -0.018078904051710948, 0.006841809908273834, 0.01833712658819791, -0.011896660303553859, -0.013593868948456916, 0.002188182475790726, 0.02177120083270365, -0.026747972399023875, 0.015502066790506641, 0.017618168571061216, -0.008386690218258046, -0.013641043363560139, 0.015073916505211941, 0.0008292899707286363, -0.017534778561491854,}, {0.5017267080829475, 0.011718752852060874, 0.019322179039220067, 0.008549344343798334, -0.006608448500975183, 0.008111794667962663, 0.020850684109274133, -0.021591032564881842, -0.03422434388873759, 0.020075923516116395, -0.011258052813291935, 0.006096202490183885, -0.0064840242667144835, 0.006602889762828003, 0.013995035599923324, 0.016109188760650976, -0.005394538576840592, -0.0018440004047645417, 0.0007517324359457994, 0.01736386021204072, 0.005098515262807945, -0.039479738672228426, -0.0014822661109888562, -9.871268328236711e-05, -0.012347389976337525, -0.014781072260125133, 0.02037757113486535, 0.006714443547086354, 0.005780029939463036, 0.02767090347364229, 0.01087525071044302, 0.013250563040370245,}, {0.4967552202452383, -0.006762112088033033, -0.011599002662847881, -0.010269993763233789, -0.026097929974874347, 0.0040821783058365, 0.007462882118899224, -0.008474448836380271, -0.00998200988269695, 0.026118217607614166, 0.021647789471096295, 0.01328248658918883, -0.004949233117690303, -0.01101961973216688, -0.01435456699517769, -0.009054626657026433, -0.03499182352982877, -0.027837420006368283, -0.01740514277589266, 0.006077133469727564, 0.012164069335921684, -0.009383600687328957, 0.02765443079590423, 0.02882997000931533, 0.0016710267743946493, -0.0036308193791352965, 0.003029054210135254, -0.011312174999718518, -0.014528104334145468, -0.018822647362063738, 0.00021701740950689732, 0.00032700815100798465,}, {0.491212821633066, 0.0023659588885512504, 0.0040326903976680584, -0.004186875093785921, -0.008476449250753342, 0.011468167389159473, 0.0009497447813776851, 0.0062099552300659494, -0.0011605422250612473, 0.005734765182840088, 0.02138622250184327, 0.017088671643369677, 0.0005394472180103555, 0.0028803006290032086, -0.0035863490192694553, -0.0082660259403773, -0.025892973119627516, -0.02196974890497912, -0.00043573842388980625, -0.0006514462461366139, -0.00927849602509139, -0.00527893732301265, 0.018461759211273365, 0.02343803069911254, 0.009126861397984836, -0.015125084909413385, -0.0043598479509703025, -0.0007620220375390608, 0.0014014388718260815, 0.0023523317562391287, 0.007724914238123322, 0.01623238852079139,}, {0.4987119920029026, 0.0023183304994720084, 0.00406267528466216, -0.00019309055989045874, 0.0017894062098721825, 0.015783899742572462, 0.003425675807618096, 0.005582458560567785, 0.0009596865613776755, 0.0004707280320951205, 0.012233744713469795, 0.01226627048895897, -0.0011773851638003621, 0.0022064512122923537, 0.0035258801480370972, -0.0006064317498532792, -0.01829180156612011, -0.01343275901043713, -0.0019611036691692552, -0.0003488706327423708, -0.010797112591872004, -0.006528078251039364, 0.0063985984123044425, 0.012275787882818322, 0.0015631168421025555, -0.009281506312418732, -0.0017568709456691949, 0.0038517000193973886, 0.0041059401088260755, 0.002475882288817624, 0.0019756439338035436, 0.008914394137576694,}, {0.4992640159579281, 0.001755421256453673, 0.0009010090536524715, 0.0017910790337779662, 0.003773900013324309, 0.006807755691057167, 0.005694190505196621, 0.006417618301758031, 0.0015645269985624744, 0.0016014202324507233, 0.0056979121882086035, 0.008864546390471708, 0.0009001575264595382, 0.0028867104583218525, -7.074846542363632e-05, -0.0026960687912269614, -0.013686380746733185, -0.009680643942384872, -0.0029619972294258347, -0.004790905243307655, -0.00717108798022522, -0.001568954294251241, 0.002546420722903897, 0.009386290243542242, -0.0002945558351170445, -0.004006390767637769, -0.0009236054558012462, 0.001807315586753195, 0.0036251666043059533, 0.0004998967469849801, 0.000933756755855534, 0.0060895277630168745,}, {0.4972522028529561, 0.0022803719738149365, 0.0006838598293294929, -0.002526641297501439, 0.0016495605782033294, 0.0074830463484637285, 0.0036837617031013547, 0.005641565231851911, 0.003348362076719588, 0.0012347733176677061, 0.0016206624087020086, 0.0016235359242912518, -2.3697933215857876e-06, 0.0026648681916910206, -0.002611077762786738, -0.0017240143455297507, -0.005261117030441153, -0.003629702063450783, -0.003139228906279272, -0.004836050901358947, -0.0036261349306895736, -0.0005784924129679358, -0.0024787900219400676, 0.001302673152989895, -0.0004286712010116157, -0.0032874987252823574, -0.0024785974221277637, 0.0028716097002169628, 0.005178783668086138, 0.0019502287186406646, 0.0008087386944846551, 0.0020367966423472045,}, {0.50033310596647, 0.002904882153827424, -0.0011124058999954062, -0.003572642855190544, -0.0025678987727389625, 0.0007663216183399779, -0.0014131026087121725, -0.0008465625440427253, -0.0010664676552658823, -0.0013399677180245232, -0.0027770398642615675, -0.00029051178177982244, -0.00047158774407275896, 0.0019826522844062933, -7.387757792374239e-05, 0.00013330033266048216, -0.0003629841284144264, 0.002288697907626012, 0.0022072207487768, 0.0005123068416822572, -0.00032583469126914793, 0.0023280450850747935, -0.00032279526950729497, 0.0007477205042727506, -0.0012314617125021602, -0.00206598933139614, -0.0013233551609725357, 0.0006005708691220199, 0.0008193541077876289, -0.002161368931330554, -0.004515888265559954, -0.003181926761915986,}, {0.5004277450439826, 0.0008904633296880779, -0.0019405597139215964, -0.0008083826359054303, -0.000389058345940255, -0.00019954989589966005, -0.002236478747662409, -0.0009202799374472841, -0.0005507347273279095, -0.0009824289714575332, -0.00237603795692571, 0.00014355293382816678, -0.0005386188563376901, 0.0006381421986191562, -0.0008965716159744841, 0.00053722016330555, -2.529984259740602e-05, 0.001747646070107691, 0.0009766906049379312, 0.000142767293455355, -0.0001258820665003751, 0.001975150554621912, -0.001052109976934449, -0.0010071170238982363, -0.00024086247876881558, 0.00041799493065971226, -0.001149692344970314, -0.0005967687002041695, -1.5645742707597056e-06, 0.0007017791717859319, -0.002059762769363591, -0.002071277490783701,}, {0.4996175053477274, -0.00012908164170466292, -0.0008449263774034349, -0.0006714054270494086, -0.0011478256647341869, -0.0015611019459522543, -0.0008850092469802204, -0.0004383247112030051, 1.889453022277143e-05, -0.0004140820391873518, 2.3738086065755565e-05, -0.00013925882442384897, -0.0003444454954998794, -0.0006135510870042048, 0.0005560650835007353, 0.00026205078783004285, 3.638226995200116e-05, 0.00016588270699040422, 0.0013230862928779233, -0.00048732992322938183, -0.00015444131449530144, 0.0004568894033640024, 7.225293184930242e-05, 0.00023715104962655916, 9.761736779637534e-05, -6.719335438292378e-05, 2.553095837414487e-05, 8.372514341479186e-05, -0.0007066820397584072, -6.236235571051752e-05, -0.001245555817574276, -0.0009787797366823084,}, {0.5000292122404237, 9.792058371712852e-05, -0.00012496258034604086, -0.0004943944253508707, -0.0006734526478695868, -0.00047072710287473797, -0.0008115734956562711, -3.8897851263074005e-05, 4.3942166149116255e-05, -0.00019211476251855593, 0.00019440451336981328, -0.00021583379445690615, -0.0004385619264861013, 0.0006851131201460307, -1.7969236621484332e-05, 5.035574697989073e-05, 0.0007553115638455165, 0.0002631312829615627, 0.00020209250692302435, 0.000523417309749427, 0.00034000784892042454, -0.00038424045791701593, 0.00020802786329514772, 0.0002477522455095238, -0.00010588945330152909, -0.00010613663688355386, 6.73323870603395e-05, 0.0004939809009132238, -0.00027655832378964147, -0.001015860221088585, -0.00042511047183775715, -0.0006139402796732137,}, {0.5002585182582469, 0.0003782094702485864, -0.000380066975425657, -0.00016890577104612642, -0.0003633056788391102, -0.0007458237893221146, -0.00048473414566742816, 0.00016918124043139579, -0.0003212278825930016, 5.56066266770741e-05, -4.8110991634360195e-05, -0.00048421369576370034, -0.00013333069179764635, 8.80725661899925e-05, 0.00010432222091706399, 0.0003645971011904867, 5.785571330845455e-05, 0.0005575357305873212, 0.0002197580218534582, 7.711459149611185e-05, 0.0004602826151231303, -0.00035008726708864136, 4.659285250290333e-06, 0.00036272301093010113, -0.0004656781096776285, -8.736324246516222e-05, 0.00017879219534955152, -0.00025455789534330084, 0.00012512799266298542, -0.0006126869686037715, -0.0007292838367469168, -4.8317933288592464e-05,},},
 			wantErr: false,
 		},
 	}
@@ -156,3 +156,40 @@ func ExampleExploreLearning_fblms() {
 	//output:
 	//the step size mu with the smallest error is 0.010
 }
+
+func TestFiltFBLMS_Step(t *testing.T) {
+	rand.Seed(1)
+	L := 8
+	af := Must(NewFiltFBLMS(L, 0.01, "zeros"))
+	alt := Must(NewFiltFBLMS(L, 0.01, "zeros"))
+	for k := 0; k < 16; k++ {
+		x := misc.NewNormRandSlice(L)
+		d := misc.NewNormRandSlice(L)
+		pred := af.Predict(x)
+		y, e := af.Step(d, x)
+		if !floats.Equal(y, pred) {
+			t.Fatalf("Step() block %d y = %v, want Predict() = %v", k, y, pred)
+		}
+		for i := range e {
+			if e[i] != d[i]-y[i] {
+				t.Fatalf("Step() block %d e[%d] = %v, want %v", k, i, e[i], d[i]-y[i])
+			}
+		}
+		alt.Adapt(d, x)
+		_, _, w := af.GetParams()
+		_, _, wAlt := alt.GetParams()
+		if !floats.Equal(w, wAlt) {
+			t.Fatalf("Step() block %d w = %v, want the weights of Adapt() %v", k, w, wAlt)
+		}
+	}
+}
+
+func TestFiltFBLMS_Run_invalidRows(t *testing.T) {
+	af := Must(NewFiltFBLMS(4, 0.1, "zeros"))
+	if _, _, _, err := af.Run([][]float64{{1, 2, 3}}, [][]float64{{1, 2, 3}}); err == nil {
+		t.Errorf("Run() with rows of length 3 for n = 4: error = nil, want error")
+	}
+	if _, _, _, err := af.Run(nil, nil); err != nil {
+		t.Errorf("Run() with no blocks: error = %v, want nil", err)
+	}
+}
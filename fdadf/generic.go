@@ -0,0 +1,205 @@
+package fdadf
+
+import (
+	"fmt"
+	"math/cmplx"
+
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/pkg/errors"
+)
+
+//Float is the constraint of the sample type of the generic frequency domain adaptive filters.
+type Float interface {
+	~float32 | ~float64
+}
+
+//kindGenericFBLMS is the kind name of GenericFBLMS.
+const kindGenericFBLMS = "generic FBLMS filter"
+
+//GenericFBLMS is FBLMS filter working in float32 or float64.
+//The samples and the weights are kept in `T`, and the FFT is computed in complex128.
+//The results of Run agree with FiltFBLMS.Run within the precision of `T`.
+//Use NewGenericFBLMS to make instance.
+type GenericFBLMS[T Float] struct {
+	kind string
+	n    int
+	mu   T
+	//w is the `n` filter weights.
+	w []T
+	//xMem is the input block before the current block.
+	xMem []T
+	//work buffers of length 2n.
+	u, buf []complex128
+}
+
+//NewGenericFBLMS is constructor of generic FBLMS filter.
+//This func initialize block length `n`, update step size `mu` and filter weight `w`.
+//If `w` is nil, the weights are initialized as zeros.
+func NewGenericFBLMS[T Float](n int, mu T, w []T) (*GenericFBLMS[T], error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("the filter length `n` must be positive. n: %d", n)
+	}
+	if mu < 0 || 1000 < mu {
+		return nil, fmt.Errorf("parameter mu is not in range <%v, %v>", 0, 1000)
+	}
+	if w == nil {
+		w = make([]T, n)
+	}
+	if len(w) != n {
+		return nil, errors.New("length of w is different from n")
+	}
+	p := &GenericFBLMS[T]{kind: kindGenericFBLMS, n: n, mu: mu, w: w}
+	p.Reset()
+	p.w = w
+	return p, nil
+}
+
+//Predict calculates the new output value `y` from input array `x`.
+func (af *GenericFBLMS[T]) Predict(x []T) (y []T) {
+	y = make([]T, af.n)
+	af.output(x, y)
+	return y
+}
+
+//Adapt calculates the error `e` between desired value `d` and estimated value `y`,
+//and update filter weights according to error `e`.
+func (af *GenericFBLMS[T]) Adapt(d []T, x []T) {
+	af.Step(d, x)
+}
+
+//Step calculates the output `y` and the error `e` of the block,
+//and update filter weights according to error `e`.
+func (af *GenericFBLMS[T]) Step(d []T, x []T) (y, e []T) {
+	y = make([]T, af.n)
+	e = make([]T, af.n)
+	af.adapt(d, x, y, e)
+	return y, e
+}
+
+//Run calculates the errors `e` between desired values `d` and estimated values `y` in a row,
+//while updating filter weights according to error `e`.
+//The arg `x`: rows are samples sets, columns are input values.
+func (af *GenericFBLMS[T]) Run(d [][]T, x [][]T, opts ...RunOption) ([][]T, [][]T, [][]T, error) {
+	//measure the data and check if the dimension agree
+	N := len(x)
+	if len(d) != N {
+		return nil, nil, nil, errors.New("the length of slice d and x must agree")
+	}
+	hist := newHistoryOf[T](N, af.n, opts)
+
+	y := make([][]T, N)
+	e := make([][]T, N)
+	for k := 0; k < N; k++ {
+		if len(d[k]) != af.n || len(x[k]) != af.n {
+			return nil, nil, nil, fmt.Errorf("the length of the block %d and `n` must agree. n: %d", k, af.n)
+		}
+		if err := hist.Record(k, af.w); err != nil {
+			return nil, nil, nil, err
+		}
+		y[k] = make([]T, af.n)
+		e[k] = make([]T, af.n)
+		af.adapt(d[k], x[k], y[k], e[k])
+	}
+	return y, e, hist.Result(), nil
+}
+
+//output computes the output `y` of the block `x` and leaves the spectrum of the last two blocks in af.u.
+func (af *GenericFBLMS[T]) output(x []T, y []T) {
+	// 1 compute the output of the filter for the block kM, ..., KM + M -1
+	for i := 0; i < af.n; i++ {
+		af.u[i] = complex(float64(af.xMem[i]), 0)
+		af.u[af.n+i] = complex(float64(x[i]), 0)
+		af.buf[i] = complex(float64(af.w[i]), 0)
+		af.buf[af.n+i] = 0
+	}
+	copy(af.u, fft.FFT(af.u))
+	W := fft.FFT(af.buf)
+	for i := range af.buf {
+		af.buf[i] = W[i] * af.u[i]
+	}
+	yc := fft.IFFT(af.buf)[af.n:]
+	for i := 0; i < af.n; i++ {
+		y[i] = T(real(yc[i]))
+	}
+}
+
+//adapt writes the output `y` and the error `e` of the block and updates the weights.
+func (af *GenericFBLMS[T]) adapt(d, x, y, e []T) {
+	af.output(x, y)
+	copy(af.xMem, x)
+	for i := 0; i < af.n; i++ {
+		e[i] = d[i] - y[i]
+	}
+
+	// 2 compute the correlation vector
+	for i := 0; i < af.n; i++ {
+		af.buf[i] = 0
+		af.buf[af.n+i] = complex(float64(e[i]), 0)
+	}
+	E := fft.FFT(af.buf)
+	for i := range af.buf {
+		af.buf[i] = E[i] * cmplx.Conj(af.u[i])
+	}
+	phi := fft.IFFT(af.buf)[:af.n]
+
+	// 3 update the parameters of the filter
+	//the gradient constraint keeps only the first `n` values of the correlation.
+	for i := 0; i < af.n; i++ {
+		af.w[i] += af.mu * T(real(phi[i]))
+	}
+}
+
+//SetStepSize set a update step size mu.
+func (af *GenericFBLMS[T]) SetStepSize(mu T) error {
+	if mu < 0 || 1000 < mu {
+		return fmt.Errorf("parameter mu is not in range <%v, %v>", 0, 1000)
+	}
+	af.mu = mu
+	return nil
+}
+
+//SetWeights sets the filter weights. The length of `w` must be the block length `n`.
+//If `w` is nil, the weights are set to zeros.
+//The slice `w` is copied.
+func (af *GenericFBLMS[T]) SetWeights(w []T) error {
+	if w == nil {
+		w = make([]T, af.n)
+	}
+	if len(w) != af.n {
+		return errors.New("length of w is different from n")
+	}
+	af.w = append([]T{}, w...)
+	return nil
+}
+
+//Reset sets the filter weights and the input block memory to zeros.
+func (af *GenericFBLMS[T]) Reset() {
+	af.w = make([]T, af.n)
+	af.xMem = make([]T, af.n)
+	af.u = make([]complex128, 2*af.n)
+	af.buf = make([]complex128, 2*af.n)
+}
+
+//GetParams returns the parameters at the time this func is called.
+//parameters contains `n`: filter length, `mu`: filter update step size and `w`: filter weights.
+func (af *GenericFBLMS[T]) GetParams() (int, T, []T) {
+	return af.n, af.mu, af.w
+}
+
+//GetKindName returns the name of FDADF.
+func (af *GenericFBLMS[T]) GetKindName() string {
+	return af.kind
+}
+
+//Clone returns a deep copy of the filter.
+func (af *GenericFBLMS[T]) Clone() *GenericFBLMS[T] {
+	return &GenericFBLMS[T]{
+		kind: af.kind,
+		n:    af.n,
+		mu:   af.mu,
+		w:    append([]T{}, af.w...),
+		xMem: append([]T{}, af.xMem...),
+		u:    make([]complex128, 2*af.n),
+		buf:  make([]complex128, 2*af.n),
+	}
+}
@@ -0,0 +1,84 @@
+package fdadf
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestGenericFBLMS_Run(t *testing.T) {
+	rand.Seed(1)
+	//number of blocks
+	m := 32
+	L := 16
+	h := []float64{0.4, -0.2, 0.1, 0.05}
+	//input value
+	var x = make([][]float64, m)
+	x32 := make([][]float32, m)
+	//desired value
+	var d = make([][]float64, m)
+	d32 := make([][]float32, m)
+	s := misc.NewNormRandSlice(m * L)
+	for i := 0; i < m; i++ {
+		x[i] = s[i*L : (i+1)*L]
+		d[i] = make([]float64, L)
+		x32[i] = make([]float32, L)
+		d32[i] = make([]float32, L)
+		for j := 0; j < L; j++ {
+			for k := range h {
+				if i*L+j-k >= 0 {
+					d[i][j] += h[k] * s[i*L+j-k]
+				}
+			}
+			x32[i][j] = float32(x[i][j])
+			d32[i][j] = float32(d[i][j])
+		}
+	}
+
+	//reference: Run of FiltFBLMS
+	wantY, wantE, wantW, err := Must(NewFiltFBLMS(L, 0.01, "zeros")).Run(d, x)
+	check(err)
+
+	af64, err := NewGenericFBLMS[float64](L, 0.01, nil)
+	check(err)
+	y, e, wHist, err := af64.Run(d, x)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	af32, err := NewGenericFBLMS[float32](L, 0.01, nil)
+	check(err)
+	y32, _, wHist32, err := af32.Run(d32, x32)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for k := 0; k < m; k++ {
+		if !floats.EqualApprox(y[k], wantY[k], 1e-9) || !floats.EqualApprox(e[k], wantE[k], 1e-9) ||
+			!floats.EqualApprox(wHist[k], wantW[k], 1e-9) {
+			t.Fatalf("GenericFBLMS[float64].Run() differs from FiltFBLMS.Run() at block %d", k)
+		}
+		for i := range y[k] {
+			if d[k][i]-y[k][i] != e[k][i] {
+				t.Fatalf("GenericFBLMS[float64].Run() e[%d][%d] = %v, want %v", k, i, e[k][i], d[k][i]-y[k][i])
+			}
+		}
+		if !floats.EqualApprox(toFloat64s(y32[k]), wantY[k], 1e-4) || !floats.EqualApprox(toFloat64s(wHist32[k]), wantW[k], 1e-4) {
+			t.Fatalf("GenericFBLMS[float32].Run() differs from FiltFBLMS.Run() at block %d", k)
+		}
+	}
+	//the weights converge to the unknown system
+	_, _, w := af64.GetParams()
+	if !floats.EqualApprox(w[:len(h)], h, 0.05) {
+		t.Errorf("GenericFBLMS[float64] w = %v, want %v", w[:len(h)], h)
+	}
+}
+
+//toFloat64s converts the slice `x` to float64.
+func toFloat64s[T Float](x []T) []float64 {
+	x64 := make([]float64, len(x))
+	for i, v := range x {
+		x64[i] = float64(v)
+	}
+	return x64
+}
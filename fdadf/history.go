@@ -0,0 +1,75 @@
+package fdadf
+
+import (
+	"io"
+
+	"github.com/tetsuzawa/go-adflib/internal/adfutil"
+)
+
+//RunOption configures the weight history recorded by Run.
+//By default Run returns the weights before every block, that is `N` rows of `n` weights.
+type RunOption func(c *runConfig)
+
+//runConfig is the configuration built from RunOption.
+type runConfig struct {
+	adfutil.HistoryConfig
+}
+
+//WithoutHistory disables the weight history. Run returns nil as `wHist`.
+func WithoutHistory() RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+	}
+}
+
+//WithHistoryEvery records only every `k`-th weight snapshot, that is the weights before blocks 0, k, 2k, ....
+//The returned `wHist` has ceil(N/k) rows. It also applies to WithHistoryFunc and WithHistoryWriter.
+//If `k` is smaller than 1, every snapshot is recorded.
+func WithHistoryEvery(k int) RunOption {
+	return func(c *runConfig) {
+		if k < 1 {
+			k = 1
+		}
+		c.Every = k
+	}
+}
+
+//WithHistoryFunc streams the weight snapshots to `fn` instead of keeping them in memory.
+//`i` is the index of the block and `w` is the weights before the block.
+//`w` is only valid during the call. If `fn` returns an error, Run stops and returns it.
+//The filters working in float32 pass the weights converted to float64.
+func WithHistoryFunc(fn func(i int, w []float64) error) RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+		c.Fn = fn
+	}
+}
+
+//WithHistoryWriter streams the weight snapshots to `w` instead of keeping them in memory.
+//Each snapshot is written as `n` little-endian float64 values, also for the filters working in float32.
+//If a write fails, Run stops and returns the error.
+func WithHistoryWriter(w io.Writer) RunOption {
+	return func(c *runConfig) {
+		c.Keep = false
+		c.Writer = w
+	}
+}
+
+//newRunConfig applies `opts` to the default configuration.
+func newRunConfig(opts []RunOption) *runConfig {
+	c := &runConfig{HistoryConfig: adfutil.NewHistoryConfig()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//newHistory makes the weight history for `N` blocks and `n` weights of float64.
+func newHistory(N, n int, opts []RunOption) *adfutil.History[float64] {
+	return newHistoryOf[float64](N, n, opts)
+}
+
+//newHistoryOf makes the weight history for `N` blocks and `n` weights of type `T`.
+func newHistoryOf[T Float](N, n int, opts []RunOption) *adfutil.History[T] {
+	return adfutil.NewFloatHistory[T](newRunConfig(opts).HistoryConfig, N, n)
+}
@@ -0,0 +1,48 @@
+package fdadf
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestFiltFBLMS_Run_history(t *testing.T) {
+	rand.Seed(1)
+	//number of blocks
+	m := 10
+	L := 8
+	//input value
+	var x = make([][]float64, m)
+	//desired value
+	var d = make([][]float64, m)
+	for i := 0; i < m; i++ {
+		x[i] = misc.NewNormRandSlice(L)
+		d[i] = append([]float64{}, x[i]...)
+	}
+	_, _, full, err := Must(NewFiltFBLMS(L, 0.01, "zeros")).Run(d, x)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		opts []RunOption
+		want [][]float64
+	}{
+		{name: "default", opts: nil, want: full},
+		{name: "without history", opts: []RunOption{WithoutHistory()}, want: nil},
+		{name: "every 4", opts: []RunOption{WithHistoryEvery(4)}, want: [][]float64{full[0], full[4], full[8]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, wHist, err := Must(NewFiltFBLMS(L, 0.01, "zeros")).Run(d, x, tt.opts...)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(wHist, tt.want) {
+				t.Errorf("Run() wHist = %v, want %v", wHist, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package fdadf
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+//stateVersion is the version of the serialized filter state.
+//It must be incremented when the meaning of filterState changes.
+const stateVersion = 1
+
+//filterState is the serialized form of the complete state of a frequency domain adaptive filter.
+type filterState struct {
+	Version int       `json:"version"`
+	Kind    string    `json:"kind"`
+	N       int       `json:"n"`
+	Mu      float64   `json:"mu"`
+	W       []float64 `json:"w"`
+	XMem    []float64 `json:"x_mem,omitempty"`
+}
+
+//encodeState encodes the filter state to the binary form.
+func encodeState(s *filterState) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, errors.Wrap(err, "failed to encode filter state")
+	}
+	return buf.Bytes(), nil
+}
+
+//decodeState decodes the filter state from the binary form and checks the version.
+func decodeState(data []byte) (*filterState, error) {
+	s := new(filterState)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(s); err != nil {
+		return nil, errors.Wrap(err, "failed to decode filter state")
+	}
+	return s, checkStateVersion(s)
+}
+
+//decodeStateJSON decodes the filter state from JSON and checks the version.
+func decodeStateJSON(data []byte) (*filterState, error) {
+	s := new(filterState)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.Wrap(err, "failed to decode filter state")
+	}
+	return s, checkStateVersion(s)
+}
+
+func checkStateVersion(s *filterState) error {
+	if s.Version != stateVersion {
+		return fmt.Errorf("unsupported filter state version: %d", s.Version)
+	}
+	return nil
+}
+
+//checkState checks if the serialized state was made by the filter of `kind`,
+//and if the filter length is valid.
+func checkState(s *filterState, kind string) error {
+	if s.Kind != kind {
+		return fmt.Errorf("filter state of %q can not be loaded into %q", s.Kind, kind)
+	}
+	if s.N <= 0 {
+		return fmt.Errorf("filter length n must be positive. n: %d", s.N)
+	}
+	return nil
+}
+
+//checkStateLen checks if the length of the serialized slice is as expected.
+func checkStateLen(fs []float64, n int, name string) error {
+	if len(fs) != n {
+		return fmt.Errorf("the length of %v must be %d. len(%v): %d", name, n, name, len(fs))
+	}
+	return nil
+}
+
+//newFilterFromState makes the filter of the kind stored in `s`.
+func newFilterFromState(s *filterState) (FDAdaptiveFilter, error) {
+	var af interface {
+		FDAdaptiveFilter
+		setState(s *filterState) error
+	}
+	switch s.Kind {
+	case kindFBLMS:
+		af = new(FiltFBLMS)
+	default:
+		return nil, fmt.Errorf("unknown filter kind: %q", s.Kind)
+	}
+	if err := af.setState(s); err != nil {
+		return nil, err
+	}
+	return af, nil
+}
+
+//UnmarshalFilterBinary restores the filter saved by MarshalBinary.
+//The concrete type of the returned filter is chosen from the kind stored in `data`.
+func UnmarshalFilterBinary(data []byte) (FDAdaptiveFilter, error) {
+	s, err := decodeState(data)
+	if err != nil {
+		return nil, err
+	}
+	return newFilterFromState(s)
+}
+
+//UnmarshalFilterJSON restores the filter saved by MarshalJSON.
+//The concrete type of the returned filter is chosen from the kind stored in `data`.
+func UnmarshalFilterJSON(data []byte) (FDAdaptiveFilter, error) {
+	s, err := decodeStateJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return newFilterFromState(s)
+}
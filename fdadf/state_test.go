@@ -0,0 +1,67 @@
+package fdadf
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tetsuzawa/go-adflib/misc"
+)
+
+func TestFiltFBLMS_MarshalBinary(t *testing.T) {
+	rand.Seed(1)
+	//creation of data
+	//number of samples
+	n := 512
+	L := 32
+	m := n / L
+	//input value
+	var x = make([][]float64, m)
+	//desired value
+	var d = make([][]float64, m)
+	for i := 0; i < m; i++ {
+		x[i] = misc.NewNormRandSlice(L)
+		d[i] = make([]float64, L)
+		for j := 1; j < L; j++ {
+			d[i][j] = 0.5*x[i][j] + 0.25*x[i][j-1]
+		}
+	}
+	af := Must(NewFiltFBLMS(L, 0.01, "zeros"))
+	for i := 0; i < m/2; i++ {
+		af.Adapt(d[i], x[i])
+	}
+
+	bin, err := af.(*FiltFBLMS).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	js, err := af.(*FiltFBLMS).MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	fromBin, err := UnmarshalFilterBinary(bin)
+	if err != nil {
+		t.Fatalf("UnmarshalFilterBinary() error = %v", err)
+	}
+	fromJSON := new(FiltFBLMS)
+	if err := fromJSON.UnmarshalJSON(js); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	for i := m / 2; i < m; i++ {
+		want := af.Predict(x[i])
+		for _, restored := range []FDAdaptiveFilter{fromBin, fromJSON} {
+			if got := restored.Predict(x[i]); !reflect.DeepEqual(got, want) {
+				t.Fatalf("Predict() of restored filter = %v, want %v", got, want)
+			}
+			restored.Adapt(d[i], x[i])
+		}
+		af.Adapt(d[i], x[i])
+	}
+	_, _, want := af.GetParams()
+	for _, restored := range []FDAdaptiveFilter{fromBin, fromJSON} {
+		if _, _, got := restored.GetParams(); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetParams() w of restored filter = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package adfutil
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+//Float is the constraint of the weights of the real filters.
+type Float interface {
+	~float32 | ~float64
+}
+
+//HistoryConfig is the configuration of the weight history of Run.
+//It is set by the RunOption of the packages adf and fdadf.
+type HistoryConfig struct {
+	//Keep is whether the snapshots are kept in memory.
+	Keep bool
+	//Every is the interval of the recorded snapshots.
+	Every int
+	//Fn receives the snapshots as float64 values.
+	Fn func(i int, w []float64) error
+	//Writer receives the snapshots as little-endian float64 values.
+	Writer io.Writer
+}
+
+//NewHistoryConfig returns the default configuration, which keeps every snapshot in memory.
+func NewHistoryConfig() HistoryConfig {
+	return HistoryConfig{Keep: true, Every: 1}
+}
+
+//History records the weight history of Run according to HistoryConfig.
+type History[T any] struct {
+	c    HistoryConfig
+	hist [][]T
+	//flatten converts the weights to the float64 values passed to Fn and Writer.
+	//It may return `w` itself or use `dst` as the buffer.
+	flatten func(dst []float64, w []T) []float64
+	fbuf    []float64
+	buf     []byte
+}
+
+//NewFloatHistory makes History for `N` samples and `n` weights of type `T`.
+//Fn and Writer receive the weights converted to float64.
+func NewFloatHistory[T Float](c HistoryConfig, N, n int) *History[T] {
+	flatten := func(dst []float64, w []T) []float64 {
+		if fw, ok := any(w).([]float64); ok {
+			return fw
+		}
+		dst = dst[:len(w)]
+		for j, v := range w {
+			dst[j] = float64(v)
+		}
+		return dst
+	}
+	return newHistory(c, N, n, n, flatten)
+}
+
+//NewComplexHistory makes History for `N` samples and `n` complex weights.
+//Fn and Writer receive the real and imaginary parts interleaved, that is `2n` float64 values.
+func NewComplexHistory(c HistoryConfig, N, n int) *History[complex128] {
+	flatten := func(dst []float64, w []complex128) []float64 {
+		dst = dst[:2*len(w)]
+		for j, v := range w {
+			dst[2*j] = real(v)
+			dst[2*j+1] = imag(v)
+		}
+		return dst
+	}
+	return newHistory(c, N, n, 2*n, flatten)
+}
+
+//newHistory makes History for `N` samples and `n` weights, which are flattened to `width` float64 values.
+func newHistory[T any](c HistoryConfig, N, n, width int, flatten func(dst []float64, w []T) []float64) *History[T] {
+	h := &History[T]{c: c, flatten: flatten}
+	if c.Keep {
+		h.hist = make([][]T, (N+c.Every-1)/c.Every)
+		for i := range h.hist {
+			h.hist[i] = make([]T, n)
+		}
+	}
+	if c.Fn != nil || c.Writer != nil {
+		h.fbuf = make([]float64, width)
+	}
+	if c.Writer != nil {
+		h.buf = make([]byte, 8*width)
+	}
+	return h
+}
+
+//Record records the weights `w` before the sample `i`.
+func (h *History[T]) Record(i int, w []T) error {
+	if i%h.c.Every != 0 {
+		return nil
+	}
+	if h.c.Keep {
+		copy(h.hist[i/h.c.Every], w)
+	}
+	if h.c.Fn == nil && h.c.Writer == nil {
+		return nil
+	}
+	fw := h.flatten(h.fbuf, w)
+	if h.c.Fn != nil {
+		if err := h.c.Fn(i, fw); err != nil {
+			return errors.Wrap(err, "failed to record weight history")
+		}
+	}
+	if h.c.Writer != nil {
+		for j, v := range fw {
+			binary.LittleEndian.PutUint64(h.buf[8*j:], math.Float64bits(v))
+		}
+		if _, err := h.c.Writer.Write(h.buf[:8*len(fw)]); err != nil {
+			return errors.Wrap(err, "failed to write weight history")
+		}
+	}
+	return nil
+}
+
+//Result returns the weight history kept in memory.
+func (h *History[T]) Result() [][]T {
+	return h.hist
+}
@@ -0,0 +1,52 @@
+package adfutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestNewFloatHistory(t *testing.T) {
+	c := NewHistoryConfig()
+	c.Every = 2
+	var got [][]float64
+	c.Fn = func(i int, w []float64) error {
+		got = append(got, append([]float64{}, w...))
+		return nil
+	}
+	h := NewFloatHistory[float32](c, 3, 2)
+	for i := 0; i < 3; i++ {
+		if err := h.Record(i, []float32{float32(i), 0.5}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if want := [][]float32{{0, 0.5}, {2, 0.5}}; !reflect.DeepEqual(h.Result(), want) {
+		t.Errorf("Result() = %v, want %v", h.Result(), want)
+	}
+	if want := [][]float64{{0, 0.5}, {2, 0.5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fn received %v, want %v", got, want)
+	}
+}
+
+func TestNewComplexHistory(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewHistoryConfig()
+	c.Keep = false
+	c.Writer = &buf
+	h := NewComplexHistory(c, 1, 2)
+	if err := h.Record(0, []complex128{complex(1, 2), complex(3, 4)}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if h.Result() != nil {
+		t.Errorf("Result() = %v, want nil", h.Result())
+	}
+	//the real and imaginary parts are interleaved
+	got := make([]float64, 4)
+	if err := binary.Read(&buf, binary.LittleEndian, got); err != nil {
+		t.Fatalf("binary.Read() error = %v", err)
+	}
+	if want := []float64{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Writer received %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package adfutil
+
+import (
+	"context"
+	"sync"
+)
+
+//ParallelFor calls `fn` for each index in [0, n) on at most `workers` goroutines.
+//It stops at the first error returned by `fn` or when `ctx` is done, and returns that error.
+func ParallelFor(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	idx := make(chan int)
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	var fed int
+feed:
+	for fed = 0; fed < n; fed++ {
+		select {
+		case idx <- fed:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(idx)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if fed < n {
+		return ctx.Err()
+	}
+	return nil
+}
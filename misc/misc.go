@@ -147,3 +147,15 @@ func Unset(s []float64, i int) []float64 {
 	}
 	return append(s[:i], s[i+1:]...)
 }
+
+//CountNearZero counts the values of `fs` whose magnitude is not larger than `tol`.
+//It is useful to measure the sparsity of filter weights.
+func CountNearZero(fs []float64, tol float64) int {
+	n := 0
+	for _, f := range fs {
+		if math.Abs(f) <= tol {
+			n++
+		}
+	}
+	return n
+}
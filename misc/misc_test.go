@@ -478,3 +478,38 @@ func TestUnset(t *testing.T) {
 		})
 	}
 }
+
+func TestCountNearZero(t *testing.T) {
+	type args struct {
+		fs  []float64
+		tol float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "sparse",
+			args: args{fs: []float64{1, 1e-4, -1e-4, 0, -0.5, 1e-2}, tol: 1e-3},
+			want: 3,
+		},
+		{
+			name: "tolerance is inclusive",
+			args: args{fs: []float64{0.1, -0.1, 0.2}, tol: 0.1},
+			want: 2,
+		},
+		{
+			name: "empty",
+			args: args{fs: nil, tol: 1},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountNearZero(tt.args.fs, tt.args.tol); got != tt.want {
+				t.Errorf("CountNearZero() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}